@@ -3,6 +3,9 @@ module github.com/slajuwomi/gator
 go 1.24.3
 
 require (
-	github.com/google/uuid v1.6.0 
-	github.com/lib/pq v1.10.9 
+	github.com/google/uuid v1.6.0
+	github.com/lib/pq v1.10.9
+	golang.org/x/net v0.48.0
 )
+
+require golang.org/x/text v0.32.0 // indirect
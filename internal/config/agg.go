@@ -0,0 +1,450 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/slajuwomi/gator/internal/database"
+	"github.com/slajuwomi/gator/internal/feedparser"
+)
+
+func HandleAgg(s *State, cmd Command) error {
+	if len(cmd.Arguments) < 1 {
+		return fmt.Errorf("not enough arguments. expecting gator agg <time_between_reqs> <optional_workers>")
+	}
+	timeBetweenReqs, err := time.ParseDuration(cmd.Arguments[0])
+	if err != nil {
+		return fmt.Errorf("failure getting time between requests: %v", err)
+	}
+	workers := s.Cfg.fetchWorkers()
+	if len(cmd.Arguments) > 1 {
+		workers, err = strconv.Atoi(cmd.Arguments[1])
+		if err != nil || workers < 1 {
+			return fmt.Errorf("number of workers must be a positive integer: %v", cmd.Arguments[1])
+		}
+	}
+	batchSize := s.Cfg.fetchBatchSize()
+	if batchSize < workers {
+		batchSize = workers
+	}
+	fmt.Printf("Collecting up to %d feeds every %v with %d workers\n", batchSize, cmd.Arguments[0], workers)
+	limiter := newHostLimiter(s.Cfg.hostMaxConnections(), s.Cfg.hostRequestsPerMinute())
+	// long enough for every worker to work through its share of the batch
+	lease := s.Cfg.fetchTimeout() * time.Duration((batchSize+workers-1)/workers+1)
+	ticker := time.NewTicker(timeBetweenReqs)
+	for ; ; <-ticker.C {
+		feeds, err := claimFeedsToFetch(s, batchSize, lease)
+		if err != nil {
+			fmt.Printf("An error occurred: %v\n", err)
+			continue
+		}
+		scrapeFeeds(s, limiter, feeds, workers)
+	}
+}
+
+// claimFeedsToFetch marks the stalest due feeds as fetched in one transaction and
+// pushes their next fetch out by lease, so a second agg process skips them
+// instead of fetching the same batch. Scheduling the feed once it has been
+// fetched replaces the lease; if this process dies first, the feeds come due
+// again when it runs out.
+func claimFeedsToFetch(s *State, batchSize int, lease time.Duration) ([]database.Feed, error) {
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+	qtx := s.Db.WithTx(tx)
+	feeds, err := qtx.GetNextFeedsToFetch(context.Background(), database.GetNextFeedsToFetchParams{
+		NextFetchAt: sql.NullTime{
+			Time:  time.Now(),
+			Valid: true,
+		},
+		Limit: int32(batchSize),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get next feeds to fetch: %v", err)
+	}
+	for _, feed := range feeds {
+		err = qtx.MarkFeedFetched(context.Background(), database.MarkFeedFetchedParams{
+			LastFetchedAt: sql.NullTime{
+				Time:  time.Now(),
+				Valid: true,
+			},
+			NextFetchAt: sql.NullTime{
+				Time:  time.Now().Add(lease),
+				Valid: true,
+			},
+			UpdatedAt: time.Now(),
+			ID:        feed.ID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to mark feed fetched: %v", err)
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit claimed feeds: %v", err)
+	}
+	return feeds, nil
+}
+
+// scrapeFeeds hands the feeds out in claim order to a fixed number of workers
+// and returns once all of them are done.
+func scrapeFeeds(s *State, limiter *hostLimiter, feeds []database.Feed, workers int) {
+	jobs := make(chan database.Feed)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for feed := range jobs {
+				err := scrapeFeed(s, limiter, feed)
+				if err == nil {
+					continue
+				}
+				fmt.Printf("An error occurred fetching %v: %v\n", feed.Name, err)
+				// waiting out another feed's 429 is not this feed's fault
+				if errors.Is(err, errHostBackingOff) {
+					continue
+				}
+				// nor is being rate limited itself; try again when the host asked
+				var statusErr *HTTPStatusError
+				if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusTooManyRequests {
+					err = delayFeedFetch(s, feed, statusErr.RetryAfter)
+					if err != nil {
+						fmt.Printf("An error occurred: %v\n", err)
+					}
+					continue
+				}
+				// failing to save it is ours, so it is logged above but left
+				// for the feed to be retried once its claim runs out
+				var fetchErr *feedFetchError
+				if !errors.As(err, &fetchErr) {
+					continue
+				}
+				err = recordFeedFailure(s, feed, err)
+				if err != nil {
+					fmt.Printf("An error occurred: %v\n", err)
+				}
+			}
+		}()
+	}
+	for _, feed := range feeds {
+		jobs <- feed
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// feedFetchError is a failure to fetch or parse the feed itself, the only
+// kind of error that counts against the feed.
+type feedFetchError struct {
+	err error
+}
+
+func (e *feedFetchError) Error() string {
+	return fmt.Sprintf("error occurred running FetchFeed:\n%v", e.err)
+}
+
+func (e *feedFetchError) Unwrap() error {
+	return e.err
+}
+
+type fetchStats struct {
+	statusCode   int
+	bytes        int64
+	itemsSeen    int
+	itemsNew     int
+	itemsUpdated int
+	redirectedTo string
+}
+
+func scrapeFeed(s *State, limiter *hostLimiter, feed database.Feed) error {
+	host := feedHost(feed.Url)
+	release, err := limiter.acquire(context.Background(), host)
+	if err != nil {
+		return err
+	}
+	startedAt := time.Now()
+	savedFeed, stats, err := fetchAndSaveFeed(s, limiter, host, feed, release)
+	logErr := recordFeedFetch(s, savedFeed, startedAt, stats, err)
+	if logErr != nil {
+		fmt.Printf("An error occurred: %v\n", logErr)
+	}
+	return err
+}
+
+func fetchAndSaveFeed(s *State, limiter *hostLimiter, host string, feed database.Feed, release func()) (database.Feed, fetchStats, error) {
+	var stats fetchStats
+	fetchResult, err := FetchFeed(context.Background(), s.Cfg, feed.Url, feed.Etag.String, feed.LastModified.String)
+	release()
+	if fetchResult != nil {
+		stats.statusCode = fetchResult.StatusCode
+		stats.bytes = fetchResult.Bytes
+	}
+	if err != nil {
+		var statusErr *HTTPStatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusTooManyRequests {
+			limiter.backoff(host, statusErr.RetryAfter)
+		}
+		return feed, stats, &feedFetchError{err: err}
+	}
+	if fetchResult.Feed != nil {
+		stats.itemsSeen = len(fetchResult.Feed.Items)
+	}
+	// the fetch happens outside the transaction so it is only held while writing
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return feed, stats, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+	workerState := *s
+	workerState.Db = s.Db.WithTx(tx)
+	// a feed that moved onto an existing feed is merged into it, so the
+	// rest of the save and the fetch log belong to that feed
+	target := feed
+	if fetchResult.MovedTo != "" && fetchResult.MovedTo != feed.Url {
+		movedFeed, err := moveFeed(&workerState, feed, fetchResult.MovedTo)
+		if err != nil {
+			return feed, stats, err
+		}
+		stats.redirectedTo = fetchResult.MovedTo
+		target = movedFeed
+	}
+	added, updated, err := saveFetchResult(&workerState, target, fetchResult)
+	if err != nil {
+		return feed, stats, err
+	}
+	err = scheduleFeed(&workerState, target, fetchResult)
+	if err != nil {
+		return feed, stats, err
+	}
+	if target.ConsecutiveFailures > 0 {
+		err = workerState.Db.RecordFeedSuccess(context.Background(), database.RecordFeedSuccessParams{
+			UpdatedAt: time.Now(),
+			ID:        target.ID,
+		})
+		if err != nil {
+			return feed, stats, fmt.Errorf("failed to reset failure count: %v", err)
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		return feed, stats, fmt.Errorf("failed to commit posts for %v: %v", target.Name, err)
+	}
+	stats.itemsNew = added
+	stats.itemsUpdated = updated
+	if fetchResult.NotModified {
+		fmt.Printf("%v has not changed since the last fetch\n", target.Name)
+		return target, stats, nil
+	}
+	fmt.Printf("%v: %d added, %d updated\n", target.Name, added, updated)
+	return target, stats, nil
+}
+
+func saveFetchResult(s *State, feed database.Feed, fetchResult *FetchResult) (int, int, error) {
+	err := s.Db.UpdateFeedCacheHeaders(context.Background(), database.UpdateFeedCacheHeadersParams{
+		Etag: sql.NullString{
+			String: fetchResult.ETag,
+			Valid:  fetchResult.ETag != "",
+		},
+		LastModified: sql.NullString{
+			String: fetchResult.LastModified,
+			Valid:  fetchResult.LastModified != "",
+		},
+		UpdatedAt: time.Now(),
+		ID:        feed.ID,
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to store cache headers: %v", err)
+	}
+	if fetchResult.NotModified {
+		return 0, 0, nil
+	}
+	err = s.Db.UpdateFeedMetadata(context.Background(), database.UpdateFeedMetadataParams{
+		Description: sql.NullString{
+			String: fetchResult.Feed.Description,
+			Valid:  fetchResult.Feed.Description != "",
+		},
+		SiteUrl: sql.NullString{
+			String: fetchResult.Feed.Link,
+			Valid:  fetchResult.Feed.Link != "",
+		},
+		IconUrl: sql.NullString{
+			String: fetchResult.Feed.Icon,
+			Valid:  fetchResult.Feed.Icon != "",
+		},
+		UpdatedAt: time.Now(),
+		ID:        feed.ID,
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to store feed details: %v", err)
+	}
+	fetchedAt := time.Now()
+	var added, updated int
+	for _, item := range fetchResult.Feed.Items {
+		t := item.Published
+		if t.IsZero() {
+			t = item.Updated
+		}
+		// an undated or unparsable item still gets stored, stamped with the
+		// fetch time, but updating it keeps the time it was first stored with
+		hasDate := !t.IsZero()
+		if !hasDate {
+			t = fetchedAt
+		}
+		guid := postGUID(item)
+		// posts stored before guids existed were keyed by their link, so
+		// give such a post the item's real guid instead of inserting it again
+		if guid != item.Link && item.Link != "" {
+			err = s.Db.AdoptLegacyPostGUID(context.Background(), database.AdoptLegacyPostGUIDParams{
+				Guid:   guid,
+				FeedID: feed.ID,
+				Url:    item.Link,
+			})
+			if err != nil {
+				return 0, 0, fmt.Errorf("failed to match existing post by url: %v", err)
+			}
+		}
+		contentHash := postContentHash(item)
+		// posts stored before content hashes existed have an empty one; take
+		// the current hash as their baseline rather than treating them as edited
+		err = s.Db.AdoptLegacyPostHash(context.Background(), database.AdoptLegacyPostHashParams{
+			ContentHash: contentHash,
+			FeedID:      feed.ID,
+			Guid:        guid,
+		})
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to set content hash of existing post: %v", err)
+		}
+		newPostID := uuid.New()
+		post, err := s.Db.CreatePosts(context.Background(), database.CreatePostsParams{
+			ID:          newPostID,
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+			Title:       item.Title,
+			Url:         item.Link,
+			Description: item.Description,
+			PublishedAt: t,
+			FeedID:      feed.ID,
+			Guid:        guid,
+			ItemUpdatedAt: sql.NullTime{
+				Time:  item.Updated,
+				Valid: !item.Updated.IsZero(),
+			},
+			ContentHash: contentHash,
+			Content:     item.Content,
+			Authors:     item.Authors,
+			Categories:  item.Categories,
+			HasDate:     hasDate,
+		})
+		// no row back means the post already exists with the same content
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return 0, 0, fmt.Errorf("error adding posts to database: %v", err)
+		}
+		err = saveEnclosures(s, post.ID, item.Enclosures)
+		if err != nil {
+			return 0, 0, err
+		}
+		if post.ID == newPostID {
+			added++
+			fmt.Printf("Added %v post from %v to database. It can now be browsed.\n", post.Title, feed.Name)
+		} else {
+			updated++
+			fmt.Printf("Updated %v post from %v in database.\n", post.Title, feed.Name)
+		}
+	}
+	return added, updated, nil
+}
+
+// postGUID falls back to the link, then the title, for items that carry no guid/id.
+func postGUID(item feedparser.Item) string {
+	if item.GUID != "" {
+		return item.GUID
+	}
+	if item.Link != "" {
+		return item.Link
+	}
+	return item.Title
+}
+
+func postContentHash(item feedparser.Item) string {
+	hash := sha256.New()
+	for _, field := range []string{
+		item.Title,
+		item.Link,
+		item.Description,
+		item.Published.UTC().Format(time.RFC3339),
+		item.Updated.UTC().Format(time.RFC3339),
+		item.Content,
+		strings.Join(item.Authors, "\x1f"),
+		strings.Join(item.Categories, "\x1f"),
+	} {
+		hash.Write([]byte(field))
+		hash.Write([]byte{0})
+	}
+	for _, enclosure := range item.Enclosures {
+		fmt.Fprintf(hash, "%s\x1f%s\x1f%d\x1f%d\x1f%d\x1f%s\x00", enclosure.URL, enclosure.Type, enclosure.Length, enclosure.Duration, enclosure.Episode, enclosure.Thumbnail)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// saveEnclosures makes the post's stored enclosures match enclosures,
+// dropping any the item no longer lists.
+func saveEnclosures(s *State, postID uuid.UUID, enclosures []feedparser.Enclosure) error {
+	urls := []string{}
+	for _, enclosure := range enclosures {
+		if enclosure.URL == "" {
+			continue
+		}
+		urls = append(urls, enclosure.URL)
+		_, err := s.Db.CreatePostEnclosure(context.Background(), database.CreatePostEnclosureParams{
+			ID:        uuid.New(),
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+			PostID:    postID,
+			Url:       enclosure.URL,
+			MimeType:  enclosure.Type,
+			Length: sql.NullInt64{
+				Int64: enclosure.Length,
+				Valid: enclosure.Length > 0,
+			},
+			DurationSeconds: sql.NullInt32{
+				Int32: int32(enclosure.Duration / time.Second),
+				Valid: enclosure.Duration > 0,
+			},
+			Episode: sql.NullInt32{
+				Int32: int32(enclosure.Episode),
+				Valid: enclosure.Episode > 0,
+			},
+			ThumbnailUrl: sql.NullString{
+				String: enclosure.Thumbnail,
+				Valid:  enclosure.Thumbnail != "",
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("error adding enclosure to database: %v", err)
+		}
+	}
+	err := s.Db.DeleteStalePostEnclosures(context.Background(), database.DeleteStalePostEnclosuresParams{
+		PostID: postID,
+		Urls:   urls,
+	})
+	if err != nil {
+		return fmt.Errorf("error removing old enclosures from database: %v", err)
+	}
+	return nil
+}
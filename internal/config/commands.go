@@ -3,12 +3,8 @@ package config
 import (
 	"context"
 	"database/sql"
-	"encoding/xml"
 	"errors"
 	"fmt"
-	"html"
-	"io"
-	"net/http"
 	"strconv"
 	"strings"
 	"time"
@@ -18,9 +14,10 @@ import (
 )
 
 type State struct {
-	Db  *database.Queries
-	Cfg *Config
-	Ctx context.Context
+	Db   *database.Queries
+	Conn *sql.DB
+	Cfg  *Config
+	Ctx  context.Context
 }
 
 type Command struct {
@@ -32,22 +29,6 @@ type Commands struct {
 	AllCommands map[string]func(*State, Command) error
 }
 
-type RSSFeed struct {
-	Channel struct {
-		Title       string    `xml:"title"`
-		Link        string    `xml:"link"`
-		Description string    `xml:"description"`
-		Item        []RSSItem `xml:"item"`
-	} `xml:"channel"`
-}
-
-type RSSItem struct {
-	Title       string `xml:"title"`
-	Link        string `xml:"link"`
-	Description string `xml:"description"`
-	PubDate     string `xml:"pubDate"`
-}
-
 func (c *Commands) Run(s *State, cmd Command) error {
 	err := c.AllCommands[cmd.CommandName](s, cmd)
 	if err != nil {
@@ -122,131 +103,63 @@ func HandleGetAllUsers(s *State, cmd Command) error {
 	return nil
 }
 
-func FetchFeed(ctx context.Context, feedURL string) (*RSSFeed, error) {
-	var newRSSFeed RSSFeed
-	client := &http.Client{
-		Timeout: 5 * time.Second,
-	}
-	req, err := http.NewRequestWithContext(ctx, "GET", feedURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create new request:\n%v", err)
+func HandleAddFeed(s *State, cmd Command, user database.User) error {
+	if len(cmd.Arguments) < 1 {
+		return fmt.Errorf("not enough arguments. expecting addfeed <optional_name> url")
 	}
-	req.Header.Set("User-Agent", "gator")
-	res, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("FetchFeed client sending http request failed:\n%v", err)
+	name := ""
+	pageURL := cmd.Arguments[0]
+	if len(cmd.Arguments) > 1 {
+		name = cmd.Arguments[0]
+		pageURL = cmd.Arguments[1]
 	}
-	defer res.Body.Close()
-
-	body, err := io.ReadAll(res.Body)
+	feedURL, fetchResult, err := resolveFeedURL(s, pageURL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read request body:\n%v", err)
+		return err
 	}
-	err = xml.Unmarshal(body, &newRSSFeed)
-	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal XML into struct:\n%v", err)
+	if name == "" {
+		name = fetchResult.Feed.Title
 	}
-	newRSSFeed.Channel.Title = html.UnescapeString(newRSSFeed.Channel.Title)
-	newRSSFeed.Channel.Description = html.UnescapeString(newRSSFeed.Channel.Description)
-	for i, item := range newRSSFeed.Channel.Item {
-		item.Title = html.UnescapeString(item.Title)
-		item.Description = html.UnescapeString(item.Description)
-		newRSSFeed.Channel.Item[i] = item
+	if name == "" {
+		return fmt.Errorf("%v has no title. pass a name with gator addfeed <name> %v", feedURL, feedURL)
 	}
-	return &newRSSFeed, nil
-}
 
-func HandleAgg(s *State, cmd Command) error {
-	if len(cmd.Arguments) < 1 {
-		return fmt.Errorf("not enough arguments. expecting gator agg <time_between_reqs>")
-	}
-	timeBetweenReqs, err := time.ParseDuration(cmd.Arguments[0])
-	if err != nil {
-		return fmt.Errorf("failure getting time between requests: %v", err)
-	}
-	fmt.Printf("Collecting feeds every %v\n", cmd.Arguments[0])
-	ticker := time.NewTicker(timeBetweenReqs)
-	for ; ; <-ticker.C {
-		scrapeFeeds(s)
-	}
-}
-func parsePublishedAt(s string) (time.Time, error) {
-	var t time.Time
-	var err error
-	layouts := []string{time.RFC1123Z, time.RFC1123, time.RFC850}
-	for _, layout := range layouts {
-		t, err = time.Parse(layout, s)
-		if err == nil {
-			return t, nil
-		} else {
-			fmt.Println("invalid layout")
-		}
-	}
-	if t.IsZero() {
-		return t, fmt.Errorf("no layout matched: %v", err)
-	}
-	return t, nil
-}
-func scrapeFeeds(s *State) error {
-	nextFeedToFetch, err := s.Db.GetNextFeedToFetch(context.Background())
+	// the feed, its current posts and the follow are saved together so a
+	// failure part way doesn't leave a feed nobody follows
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
 	if err != nil {
-		return fmt.Errorf("failed to get next feed to fetch: %v", err)
+		return fmt.Errorf("failed to begin transaction: %v", err)
 	}
-	s.Db.MarkFeedFetched(context.Background(), database.MarkFeedFetchedParams{
+	defer tx.Rollback()
+	txState := *s
+	txState.Db = s.Db.WithTx(tx)
+	newFeed, err := txState.Db.CreateFeed(context.Background(), database.CreateFeedParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
 		LastFetchedAt: sql.NullTime{
 			Time:  time.Now(),
 			Valid: true,
 		},
-		UpdatedAt: time.Now(),
-		ID:        nextFeedToFetch.ID,
-	})
-	feedStruct, err := FetchFeed(context.Background(), nextFeedToFetch.Url)
+		Name:   name,
+		UserID: user.ID,
+		Url:    feedURL})
 	if err != nil {
-		return fmt.Errorf("error occurred running FetchFeed:\n%v", err)
+		return fmt.Errorf("error creating feed: %v", err)
 	}
-	for _, item := range feedStruct.Channel.Item {
-		t, err := parsePublishedAt(item.PubDate)
-		if err != nil {
-			return fmt.Errorf("parsePublishedAt failed: %v", err)
-		}
-		post, err := s.Db.CreatePosts(context.Background(), database.CreatePostsParams{
-			ID:          uuid.New(),
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
-			Title:       item.Title,
-			Url:         item.Link,
-			Description: item.Description,
-			PublishedAt: t,
-			FeedID:      nextFeedToFetch.ID,
-		})
-		if err != nil {
-			if strings.Contains(err.Error(), "duplicate key value violates unique constraint") {
-				continue
-			}
-			return fmt.Errorf("error adding posts to database: %v", err)
-		}
-		fmt.Printf("Added %v post from %v to database. It can now be browsed.\n", post.Title, nextFeedToFetch.Name)
+	fmt.Printf("Created feed: %v (%v)\n", newFeed.Name, newFeed.Url)
+	if fetchResult.Feed.Description != "" {
+		fmt.Printf("%v\n", fetchResult.Feed.Description)
 	}
-	return nil
-}
-func HandleAddFeed(s *State, cmd Command, user database.User) error {
-	if len(cmd.Arguments) < 2 {
-		return fmt.Errorf("not enough arguments. expecting addfeed url_name actual_url")
+	added, _, err := saveFetchResult(&txState, newFeed, fetchResult)
+	if err != nil {
+		return err
 	}
-
-	newFeed, err := s.Db.CreateFeed(context.Background(), database.CreateFeedParams{
-		ID:            uuid.New(),
-		CreatedAt:     time.Now(),
-		UpdatedAt:     time.Now(),
-		LastFetchedAt: sql.NullTime{},
-		Name:          cmd.Arguments[0],
-		UserID:        user.ID,
-		Url:           cmd.Arguments[1]})
+	err = scheduleFeed(&txState, newFeed, fetchResult)
 	if err != nil {
-		return fmt.Errorf("error creating feed: %v", err)
+		return err
 	}
-	fmt.Printf("Created feed: %+v\n", newFeed)
-	insertFeedFollow, err := s.Db.CreateFeedFollow(context.Background(), database.CreateFeedFollowParams{
+	insertFeedFollow, err := txState.Db.CreateFeedFollow(context.Background(), database.CreateFeedFollowParams{
 		ID:        uuid.New(),
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
@@ -256,11 +169,19 @@ func HandleAddFeed(s *State, cmd Command, user database.User) error {
 	if err != nil {
 		return fmt.Errorf("error creating feed follow while adding feed: %v", err)
 	}
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("failed to commit new feed: %v", err)
+	}
+	fmt.Printf("Imported %d posts from %v\n", added, newFeed.Name)
 	fmt.Printf("%v now following feed: %v\n", user.Name, insertFeedFollow.FeedName)
 	return nil
 }
 
 func HandleGetAllFeeds(s *State, cmd Command) error {
+	if len(cmd.Arguments) > 0 && cmd.Arguments[0] == "--broken" {
+		return printBrokenFeeds(s)
+	}
 	dbFeeds, err := s.Db.GetFeeds(context.Background())
 	if err != nil {
 		return fmt.Errorf("error getting all feeds: %v", err)
@@ -276,6 +197,25 @@ func HandleGetAllFeeds(s *State, cmd Command) error {
 		}
 		fmt.Printf("* Creator of Feed: %v\n", creatorUserName.Name)
 		fmt.Printf("* URL of Feed: %v\n", feed.Url)
+		if feed.SiteUrl.Valid {
+			fmt.Printf("* Website: %v\n", feed.SiteUrl.String)
+		}
+		if feed.Description.Valid {
+			fmt.Printf("* Description: %v\n", feed.Description.String)
+		}
+		if feed.IconUrl.Valid {
+			fmt.Printf("* Icon: %v\n", feed.IconUrl.String)
+		}
+		if feed.FetchIntervalOverrideSeconds.Valid {
+			fmt.Printf("* Fetch Interval: %v (set manually)\n", time.Duration(feed.FetchIntervalOverrideSeconds.Int32)*time.Second)
+		} else {
+			fmt.Printf("* Fetch Interval: %v\n", time.Duration(feed.FetchIntervalSeconds)*time.Second)
+		}
+		if feed.DisabledAt.Valid {
+			fmt.Printf("* Disabled At: %v\n", feed.DisabledAt.Time)
+		} else if feed.NextFetchAt.Valid {
+			fmt.Printf("* Next Fetch: %v\n", feed.NextFetchAt.Time)
+		}
 		fmt.Println()
 	}
 	return nil
@@ -312,7 +252,11 @@ func HandleFollowing(s *State, cmd Command, user database.User) error {
 	}
 	fmt.Println("Currently following:")
 	for _, feed := range allFollowing {
-		fmt.Printf("* %v\n", feed.FeedName)
+		if feed.Folder.Valid {
+			fmt.Printf("* %v (%v)\n", feed.FeedName, feed.Folder.String)
+		} else {
+			fmt.Printf("* %v\n", feed.FeedName)
+		}
 	}
 	return nil
 }
@@ -341,28 +285,109 @@ func HandleBrowse(s *State, cmd Command, user database.User) error {
 	var limit int64
 	limit = 2
 	var err error
-	if len(cmd.Arguments) != 0 {
-		if cmd.Arguments[0] != "" {
-			limit, err = strconv.ParseInt(cmd.Arguments[0], 10, 64)
+	unreadOnly := true
+	for _, arg := range cmd.Arguments {
+		if arg == "--all" {
+			unreadOnly = false
+			continue
+		}
+		if arg != "" {
+			limit, err = strconv.ParseInt(arg, 10, 64)
 			if err != nil {
 				return fmt.Errorf("failed to convert to int: %v", err)
 			}
 		}
 	}
 	posts, err := s.Db.GetPostsForUser(context.Background(), database.GetPostsForUserParams{
-		UserID: user.ID,
-		Limit:  int32(limit),
+		UserID:     user.ID,
+		UnreadOnly: unreadOnly,
+		Limit:      int32(limit),
 	})
 	if err != nil {
 		return fmt.Errorf("error getting posts for user: %v", err)
 	}
-	fmt.Printf("Found %d posts for user %s:\n", len(posts), user.Name)
+	if unreadOnly {
+		fmt.Printf("Found %d unread posts for user %s:\n", len(posts), user.Name)
+	} else {
+		fmt.Printf("Found %d posts for user %s:\n", len(posts), user.Name)
+	}
 	for _, post := range posts {
 		fmt.Printf("%s from %s\n", post.PublishedAt.Format("Mon Jan 2"), post.FeedName)
 		fmt.Printf("--- %s ---\n", post.Title)
+		shortID, err := shortPostID(s, user, post.ID)
+		if err != nil {
+			return err
+		}
+		if post.ReadAt.Valid {
+			fmt.Printf("ID: %s (read)\n", shortID)
+		} else {
+			fmt.Printf("ID: %s\n", shortID)
+		}
+		if len(post.Authors) > 0 {
+			fmt.Printf("By: %s\n", strings.Join(post.Authors, ", "))
+		}
+		if len(post.Categories) > 0 {
+			fmt.Printf("Categories: %s\n", strings.Join(post.Categories, ", "))
+		}
 		fmt.Printf("    %v\n", post.Description)
-		fmt.Printf("Link: %s\n", post.Url_2)
+		if post.Content != "" && post.Content != post.Description {
+			fmt.Printf("Content:\n    %v\n", post.Content)
+		}
+		fmt.Printf("Link: %s\n", post.Url)
 		fmt.Println()
 	}
 	return nil
 }
+
+func HandleEpisodes(s *State, cmd Command, user database.User) error {
+	var limit int64
+	limit = 10
+	var err error
+	if len(cmd.Arguments) != 0 {
+		limit, err = strconv.ParseInt(cmd.Arguments[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("failed to convert to int: %v", err)
+		}
+	}
+	episodes, err := s.Db.GetEpisodesForUser(context.Background(), database.GetEpisodesForUserParams{
+		UserID: user.ID,
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		return fmt.Errorf("error getting episodes for user: %v", err)
+	}
+	fmt.Printf("Found %d episodes for user %s:\n", len(episodes), user.Name)
+	for _, episode := range episodes {
+		fmt.Printf("%s from %s\n", episode.PublishedAt.Format("Mon Jan 2"), episode.FeedName)
+		if episode.Episode.Valid {
+			fmt.Printf("--- #%d %s ---\n", episode.Episode.Int32, episode.PostTitle)
+		} else {
+			fmt.Printf("--- %s ---\n", episode.PostTitle)
+		}
+		fmt.Printf("Media: %s\n", episode.Url)
+		if episode.MimeType != "" {
+			fmt.Printf("Type: %s\n", episode.MimeType)
+		}
+		if episode.Length.Valid {
+			fmt.Printf("Size: %s\n", formatBytes(episode.Length.Int64))
+		}
+		if episode.DurationSeconds.Valid {
+			fmt.Printf("Duration: %v\n", time.Duration(episode.DurationSeconds.Int32)*time.Second)
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -3,11 +3,121 @@ package config
 import (
 	"encoding/json"
 	"os"
+	"time"
+)
+
+const (
+	defaultFetchWorkers          = 4
+	defaultFetchBatchSize        = 20
+	defaultHostMaxConnections    = 2
+	defaultHostRequestsPerMinute = 30
+	defaultMinFetchInterval      = 15 * time.Minute
+	defaultMaxFetchInterval      = 24 * time.Hour
+	defaultMaxFailures           = 10
+	defaultFetchTimeout          = 30 * time.Second
+	defaultConnectTimeout        = 10 * time.Second
+	defaultMaxFeedBytes          = 10 << 20
 )
 
 type Config struct {
 	DbUrl           string `json:"db_url"`
 	CurrentUserName string `json:"current_user_name"`
+	FetchWorkers    int    `json:"fetch_workers,omitempty"`
+	FetchBatchSize  int    `json:"fetch_batch_size,omitempty"`
+
+	HostMaxConnections    int `json:"host_max_connections,omitempty"`
+	HostRequestsPerMinute int `json:"host_requests_per_minute,omitempty"`
+
+	MinFetchInterval string `json:"min_fetch_interval,omitempty"`
+	MaxFetchInterval string `json:"max_fetch_interval,omitempty"`
+
+	MaxConsecutiveFailures int `json:"max_consecutive_failures,omitempty"`
+
+	FetchTimeout   string `json:"fetch_timeout,omitempty"`
+	ConnectTimeout string `json:"connect_timeout,omitempty"`
+	MaxFeedBytes   int64  `json:"max_feed_bytes,omitempty"`
+	UserAgent      string `json:"user_agent,omitempty"`
+}
+
+func (c Config) fetchWorkers() int {
+	if c.FetchWorkers > 0 {
+		return c.FetchWorkers
+	}
+	return defaultFetchWorkers
+}
+
+func (c Config) fetchBatchSize() int {
+	if c.FetchBatchSize > 0 {
+		return c.FetchBatchSize
+	}
+	return defaultFetchBatchSize
+}
+
+func (c Config) hostMaxConnections() int {
+	if c.HostMaxConnections > 0 {
+		return c.HostMaxConnections
+	}
+	return defaultHostMaxConnections
+}
+
+func (c Config) hostRequestsPerMinute() int {
+	if c.HostRequestsPerMinute > 0 {
+		return c.HostRequestsPerMinute
+	}
+	return defaultHostRequestsPerMinute
+}
+
+func (c Config) minFetchInterval() time.Duration {
+	interval, err := time.ParseDuration(c.MinFetchInterval)
+	if err != nil || interval <= 0 {
+		return defaultMinFetchInterval
+	}
+	return interval
+}
+
+func (c Config) maxFetchInterval() time.Duration {
+	interval, err := time.ParseDuration(c.MaxFetchInterval)
+	if err != nil || interval <= 0 {
+		return defaultMaxFetchInterval
+	}
+	return interval
+}
+
+func (c Config) maxConsecutiveFailures() int {
+	if c.MaxConsecutiveFailures > 0 {
+		return c.MaxConsecutiveFailures
+	}
+	return defaultMaxFailures
+}
+
+func (c Config) fetchTimeout() time.Duration {
+	timeout, err := time.ParseDuration(c.FetchTimeout)
+	if err != nil || timeout <= 0 {
+		return defaultFetchTimeout
+	}
+	return timeout
+}
+
+func (c Config) connectTimeout() time.Duration {
+	timeout, err := time.ParseDuration(c.ConnectTimeout)
+	if err != nil || timeout <= 0 {
+		return defaultConnectTimeout
+	}
+	return timeout
+}
+
+func (c Config) maxFeedBytes() int64 {
+	if c.MaxFeedBytes > 0 {
+		return c.MaxFeedBytes
+	}
+	return defaultMaxFeedBytes
+}
+
+func (c Config) userAgent() string {
+	if c.UserAgent != "" {
+		return c.UserAgent
+	}
+	return "gator/" + Version + " (+https://github.com/slajuwomi/gator)"
 }
 
 func (c Config) SetUser(userName string) error {
@@ -0,0 +1,174 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"golang.org/x/net/html/charset"
+)
+
+var feedLinkTypes = map[string]bool{
+	"application/rss+xml":   true,
+	"application/atom+xml":  true,
+	"application/rdf+xml":   true,
+	"application/feed+json": true,
+}
+
+// paths that blog engines commonly serve their feed from, tried only when a
+// page doesn't advertise any feed itself
+var commonFeedPaths = []string{
+	"/feed",
+	"/rss.xml",
+	"/atom.xml",
+	"/feed.xml",
+	"/index.xml",
+}
+
+type feedCandidate struct {
+	URL    string
+	Title  string
+	Result *FetchResult
+}
+
+// resolveFeedURL returns pageURL itself if it is a feed. Otherwise it treats
+// pageURL as a website and looks for the feed it publishes. Either way the
+// feed has been fetched, and its contents are returned with its URL.
+func resolveFeedURL(s *State, pageURL string) (string, *FetchResult, error) {
+	result, err := FetchFeed(context.Background(), s.Cfg, pageURL, "", "")
+	if err == nil && result.Feed != nil {
+		if result.MovedTo != "" {
+			return result.MovedTo, result, nil
+		}
+		return pageURL, result, nil
+	}
+	// only a page that downloaded fine but isn't a feed is worth searching
+	if result == nil || result.Page == nil {
+		return "", nil, fmt.Errorf("couldn't fetch %v: %v", pageURL, err)
+	}
+	candidates, discoverErr := discoverFeeds(context.Background(), s.Cfg, result)
+	if discoverErr != nil {
+		return "", nil, fmt.Errorf("%v is not a feed (%v) and couldn't be searched for one: %v", pageURL, err, discoverErr)
+	}
+	switch len(candidates) {
+	case 0:
+		return "", nil, fmt.Errorf("%v is not a feed and no feed could be found on it: %v", pageURL, err)
+	case 1:
+		fmt.Printf("Found feed %v at %v\n", candidates[0].Title, candidates[0].URL)
+		return candidates[0].URL, candidates[0].Result, nil
+	}
+	fmt.Printf("Found %d feeds on %v:\n", len(candidates), pageURL)
+	for _, candidate := range candidates {
+		fmt.Printf("* %v: %v\n", candidate.Title, candidate.URL)
+	}
+	return "", nil, fmt.Errorf("more than one feed found. run gator addfeed again with the url of the one you want")
+}
+
+// discoverFeeds looks for feeds published by the page FetchFeed downloaded
+// and found not to be a feed.
+func discoverFeeds(ctx context.Context, cfg *Config, page *FetchResult) ([]feedCandidate, error) {
+	links, base, err := findFeedLinks(page)
+	if err != nil {
+		return nil, err
+	}
+	candidates := validateFeedLinks(ctx, cfg, links)
+	if len(candidates) > 0 {
+		return candidates, nil
+	}
+	links = nil
+	for _, path := range commonFeedPaths {
+		links = append(links, base.ResolveReference(&url.URL{Path: path}).String())
+	}
+	return validateFeedLinks(ctx, cfg, links), nil
+}
+
+// findFeedLinks returns the feeds a page advertises with
+// <link rel="alternate">, along with the URL relative links resolve against.
+func findFeedLinks(page *FetchResult) ([]string, *url.URL, error) {
+	utf8Body, err := charset.NewReader(bytes.NewReader(page.Page), page.PageContentType)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode page: %v", err)
+	}
+	doc, err := html.Parse(utf8Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse page: %v", err)
+	}
+
+	// redirects may have moved us, so resolve against where the page really is
+	base := page.PageURL
+	var links []string
+	seen := map[string]bool{}
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.DataAtom {
+			case atom.Base:
+				if href := htmlAttr(n, "href"); href != "" {
+					if resolved, err := base.Parse(href); err == nil {
+						base = resolved
+					}
+				}
+			case atom.Link:
+				mediaType, _, _ := mime.ParseMediaType(htmlAttr(n, "type"))
+				if isAlternateLink(htmlAttr(n, "rel")) && feedLinkTypes[mediaType] {
+					if resolved, err := base.Parse(strings.TrimSpace(htmlAttr(n, "href"))); err == nil && !seen[resolved.String()] {
+						seen[resolved.String()] = true
+						links = append(links, resolved.String())
+					}
+				}
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(doc)
+	return links, base, nil
+}
+
+// validateFeedLinks keeps the links that actually fetch and parse as feeds.
+func validateFeedLinks(ctx context.Context, cfg *Config, links []string) []feedCandidate {
+	var candidates []feedCandidate
+	for _, link := range links {
+		result, err := FetchFeed(ctx, cfg, link, "", "")
+		if err != nil || result.Feed == nil {
+			continue
+		}
+		if result.MovedTo != "" {
+			link = result.MovedTo
+		}
+		title := result.Feed.Title
+		if title == "" {
+			title = "(untitled)"
+		}
+		candidates = append(candidates, feedCandidate{
+			URL:    link,
+			Title:  title,
+			Result: result,
+		})
+	}
+	return candidates
+}
+
+func htmlAttr(n *html.Node, name string) string {
+	for _, attr := range n.Attr {
+		if strings.EqualFold(attr.Key, name) {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+func isAlternateLink(rel string) bool {
+	for _, value := range strings.Fields(rel) {
+		if strings.EqualFold(value, "alternate") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,260 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/slajuwomi/gator/internal/feedparser"
+)
+
+const defaultRetryAfter = 5 * time.Minute
+
+type HTTPStatusError struct {
+	StatusCode int
+	Status     string
+	RetryAfter time.Duration
+}
+
+func (e *HTTPStatusError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("server responded %v, retry after %v", e.Status, e.RetryAfter)
+	}
+	return fmt.Sprintf("server responded %v", e.Status)
+}
+
+type FetchResult struct {
+	Feed         *feedparser.Feed
+	NotModified  bool
+	ETag         string
+	LastModified string
+	MaxAge       time.Duration
+	StatusCode   int
+	Bytes        int64
+	// MovedTo is where the feed now lives when every redirect followed on
+	// the way to it was permanent (301 or 308)
+	MovedTo string
+	// Page is the body of a response that turned out not to be a feed, such
+	// as a web page, kept with its Content-Type and final URL so it can be
+	// searched for feed links without downloading it again
+	Page            []byte
+	PageContentType string
+	PageURL         *url.URL
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// Version is reported in the default User-Agent. Release builds can set it
+// with -ldflags "-X github.com/slajuwomi/gator/internal/config.Version=v1.2.3".
+var Version = "dev"
+
+var (
+	fetchTransportOnce sync.Once
+	fetchTransport     *http.Transport
+)
+
+// newFetchTransport is shared by every fetch so connections to the same host
+// are reused. Compression is negotiated by FetchFeed itself so deflate is
+// accepted as well as gzip.
+func newFetchTransport(cfg *Config) *http.Transport {
+	fetchTransportOnce.Do(func() {
+		fetchTransport = http.DefaultTransport.(*http.Transport).Clone()
+		fetchTransport.DialContext = (&net.Dialer{
+			Timeout:   cfg.connectTimeout(),
+			KeepAlive: 30 * time.Second,
+		}).DialContext
+		fetchTransport.TLSHandshakeTimeout = cfg.connectTimeout()
+		fetchTransport.ResponseHeaderTimeout = cfg.fetchTimeout()
+		fetchTransport.DisableCompression = true
+	})
+	return fetchTransport
+}
+
+// FetchFeed sends the validators from the previous fetch, if any, so an
+// unchanged feed comes back as a 304 with NotModified set and no Feed.
+// Once a response has arrived the result is returned even alongside an
+// error, so callers can still log the status code and bytes read.
+func FetchFeed(ctx context.Context, cfg *Config, feedURL, etag, lastModified string) (*FetchResult, error) {
+	movedTo := ""
+	permanent := true
+	client := &http.Client{
+		Transport: newFetchTransport(cfg),
+		Timeout:   cfg.fetchTimeout(),
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			// once a temporary redirect is seen, later hops say nothing about
+			// where the original feed URL should point
+			if req.Response == nil || (req.Response.StatusCode != http.StatusMovedPermanently && req.Response.StatusCode != http.StatusPermanentRedirect) {
+				permanent = false
+			}
+			if permanent {
+				movedTo = req.URL.String()
+			}
+			return nil
+		},
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", feedURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new request:\n%v", err)
+	}
+	req.Header.Set("User-Agent", cfg.userAgent())
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("FetchFeed client sending http request failed:\n%v", err)
+	}
+	defer res.Body.Close()
+
+	result := &FetchResult{
+		ETag:         res.Header.Get("ETag"),
+		LastModified: res.Header.Get("Last-Modified"),
+		MaxAge:       parseMaxAge(res.Header.Get("Cache-Control")),
+		StatusCode:   res.StatusCode,
+		MovedTo:      movedTo,
+	}
+	if res.StatusCode == http.StatusTooManyRequests {
+		return result, &HTTPStatusError{
+			StatusCode: res.StatusCode,
+			Status:     res.Status,
+			RetryAfter: parseRetryAfter(res.Header.Get("Retry-After"), time.Now()),
+		}
+	}
+	if res.StatusCode == http.StatusNotModified {
+		result.NotModified = true
+		// a 304 may omit the validators, in which case the old ones still apply
+		if result.ETag == "" {
+			result.ETag = etag
+		}
+		if result.LastModified == "" {
+			result.LastModified = lastModified
+		}
+		return result, nil
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return result, &HTTPStatusError{
+			StatusCode: res.StatusCode,
+			Status:     res.Status,
+		}
+	}
+	if res.ContentLength > cfg.maxFeedBytes() {
+		return result, fmt.Errorf("feed is %d bytes, over the %d byte limit", res.ContentLength, cfg.maxFeedBytes())
+	}
+	downloaded := &countingReader{r: res.Body}
+	body, err := decodeBody(downloaded, res.Header.Get("Content-Encoding"))
+	if err != nil {
+		result.Bytes = downloaded.n
+		return result, err
+	}
+	// the limit applies after decompression so a small gzip bomb can't
+	// expand into gigabytes; reading one byte past it shows it was exceeded
+	decoded := &countingReader{r: io.LimitReader(body, cfg.maxFeedBytes()+1)}
+	content, err := io.ReadAll(decoded)
+	result.Bytes = downloaded.n
+	if err != nil {
+		return result, fmt.Errorf("failed to read feed:\n%v", err)
+	}
+	if decoded.n > cfg.maxFeedBytes() {
+		return result, fmt.Errorf("feed is over the %d byte limit", cfg.maxFeedBytes())
+	}
+	result.Feed, err = feedparser.Parse(bytes.NewReader(content), res.Header.Get("Content-Type"))
+	if errors.Is(err, feedparser.ErrUnknownFormat) {
+		result.Page = content
+		result.PageContentType = res.Header.Get("Content-Type")
+		result.PageURL = res.Request.URL
+	}
+	if err != nil {
+		return result, fmt.Errorf("failed to parse feed:\n%v", err)
+	}
+	return result, nil
+}
+
+func decodeBody(r io.Reader, contentEncoding string) (io.Reader, error) {
+	switch strings.ToLower(strings.TrimSpace(contentEncoding)) {
+	case "", "identity":
+		return r, nil
+	case "gzip", "x-gzip":
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress gzip response: %v", err)
+		}
+		return gz, nil
+	case "deflate":
+		// "deflate" is meant to be zlib-wrapped, but plenty of servers send
+		// a raw deflate stream instead
+		br := bufio.NewReader(r)
+		header, err := br.Peek(2)
+		if err == nil && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+			zr, err := zlib.NewReader(br)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decompress deflate response: %v", err)
+			}
+			return zr, nil
+		}
+		return flate.NewReader(br), nil
+	default:
+		return nil, fmt.Errorf("unsupported Content-Encoding %q", contentEncoding)
+	}
+}
+
+// parseRetryAfter understands both forms of the header: a number of seconds
+// or an HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return defaultRetryAfter
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if retryAt, err := http.ParseTime(value); err == nil {
+		if d := retryAt.Sub(now); d > 0 {
+			return d
+		}
+		return 0
+	}
+	return defaultRetryAfter
+}
+
+func parseMaxAge(cacheControl string) time.Duration {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		if !strings.EqualFold(name, "max-age") {
+			continue
+		}
+		seconds, err := strconv.Atoi(strings.Trim(value, `"`))
+		if err == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	return 0
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/slajuwomi/gator/internal/database"
+)
+
+func recordFeedFetch(s *State, feed database.Feed, startedAt time.Time, stats fetchStats, fetchErr error) error {
+	now := time.Now()
+	var statusCode sql.NullInt32
+	if stats.statusCode != 0 {
+		statusCode = sql.NullInt32{
+			Int32: int32(stats.statusCode),
+			Valid: true,
+		}
+	}
+	var errText sql.NullString
+	if fetchErr != nil {
+		errText = sql.NullString{
+			String: fetchErr.Error(),
+			Valid:  true,
+		}
+	}
+	var redirectedTo sql.NullString
+	if stats.redirectedTo != "" {
+		redirectedTo = sql.NullString{
+			String: stats.redirectedTo,
+			Valid:  true,
+		}
+	}
+	_, err := s.Db.CreateFeedFetch(context.Background(), database.CreateFeedFetchParams{
+		ID:           uuid.New(),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		FeedID:       feed.ID,
+		StartedAt:    startedAt,
+		DurationMs:   int32(now.Sub(startedAt).Milliseconds()),
+		StatusCode:   statusCode,
+		Bytes:        stats.bytes,
+		ItemsSeen:    int32(stats.itemsSeen),
+		ItemsNew:     int32(stats.itemsNew),
+		ItemsUpdated: int32(stats.itemsUpdated),
+		Error:        errText,
+		RedirectedTo: redirectedTo,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to log fetch of %v: %v", feed.Name, err)
+	}
+	return nil
+}
+
+func HandleFetchLog(s *State, cmd Command, user database.User) error {
+	if len(cmd.Arguments) < 1 {
+		return errors.New("not enough arguments. expecting gator fetchlog <feed_url> [limit]")
+	}
+	var limit int64
+	limit = 10
+	var err error
+	if len(cmd.Arguments) > 1 {
+		limit, err = strconv.ParseInt(cmd.Arguments[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("failed to convert to int: %v", err)
+		}
+	}
+	feed, err := s.Db.GetFeedByURL(context.Background(), cmd.Arguments[0])
+	if err != nil {
+		return fmt.Errorf("couldn't get feed with url: %v", err)
+	}
+	fetches, err := s.Db.GetFeedFetchesForFeed(context.Background(), database.GetFeedFetchesForFeedParams{
+		FeedID: feed.ID,
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		return fmt.Errorf("error getting fetch log: %v", err)
+	}
+	if len(fetches) == 0 {
+		fmt.Printf("%v has not been fetched yet\n", feed.Name)
+		return nil
+	}
+	fmt.Printf("Last %d fetches of %v:\n", len(fetches), feed.Name)
+	for _, fetch := range fetches {
+		fmt.Printf("* Started At: %v\n", fetch.StartedAt)
+		fmt.Printf("* Duration: %v\n", time.Duration(fetch.DurationMs)*time.Millisecond)
+		if fetch.StatusCode.Valid {
+			fmt.Printf("* HTTP Status: %d\n", fetch.StatusCode.Int32)
+		}
+		if fetch.RedirectedTo.Valid {
+			fmt.Printf("* Moved Permanently To: %v\n", fetch.RedirectedTo.String)
+		}
+		fmt.Printf("* Downloaded: %s\n", formatBytes(fetch.Bytes))
+		fmt.Printf("* Items: %d seen, %d added, %d updated\n", fetch.ItemsSeen, fetch.ItemsNew, fetch.ItemsUpdated)
+		if fetch.Error.Valid {
+			fmt.Printf("* Error: %v\n", fetch.Error.String)
+		}
+		fmt.Println()
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/slajuwomi/gator/internal/database"
+)
+
+// failureBackoff doubles the feed's usual interval for every failure in a
+// row, up to the longest interval agg would otherwise wait.
+func failureBackoff(cfg *Config, feed database.Feed, failures int32) time.Duration {
+	backoff := time.Duration(feed.FetchIntervalSeconds) * time.Second
+	if feed.FetchIntervalOverrideSeconds.Valid {
+		backoff = time.Duration(feed.FetchIntervalOverrideSeconds.Int32) * time.Second
+	}
+	if backoff < cfg.minFetchInterval() {
+		backoff = cfg.minFetchInterval()
+	}
+	for i := int32(1); i < failures && backoff < cfg.maxFetchInterval(); i++ {
+		backoff *= 2
+	}
+	if backoff > cfg.maxFetchInterval() {
+		backoff = cfg.maxFetchInterval()
+	}
+	return backoff
+}
+
+func recordFeedFailure(s *State, feed database.Feed, fetchErr error) error {
+	now := time.Now()
+	failures := feed.ConsecutiveFailures + 1
+	var disabledAt sql.NullTime
+	if failures >= int32(s.Cfg.maxConsecutiveFailures()) {
+		disabledAt = sql.NullTime{
+			Time:  now,
+			Valid: true,
+		}
+		fmt.Printf("%v failed %d times in a row and has been disabled. Run gator feeds --broken to review it\n", feed.Name, failures)
+	}
+	err := s.Db.RecordFeedFailure(context.Background(), database.RecordFeedFailureParams{
+		LastError: sql.NullString{
+			String: fetchErr.Error(),
+			Valid:  true,
+		},
+		LastErrorAt: sql.NullTime{
+			Time:  now,
+			Valid: true,
+		},
+		ConsecutiveFailures: failures,
+		NextFetchAt: sql.NullTime{
+			Time:  now.Add(failureBackoff(s.Cfg, feed, failures)),
+			Valid: true,
+		},
+		DisabledAt: disabledAt,
+		UpdatedAt:  now,
+		ID:         feed.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record fetch failure for %v: %v", feed.Name, err)
+	}
+	return nil
+}
+
+// delayFeedFetch pushes the feed's next fetch back without counting a
+// failure against it, for when the host asked us to wait.
+func delayFeedFetch(s *State, feed database.Feed, delay time.Duration) error {
+	err := s.Db.DelayFeedFetch(context.Background(), database.DelayFeedFetchParams{
+		NextFetchAt: sql.NullTime{
+			Time:  time.Now().Add(delay),
+			Valid: true,
+		},
+		UpdatedAt: time.Now(),
+		ID:        feed.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delay next fetch of %v: %v", feed.Name, err)
+	}
+	return nil
+}
+
+func printBrokenFeeds(s *State) error {
+	brokenFeeds, err := s.Db.GetBrokenFeeds(context.Background())
+	if err != nil {
+		return fmt.Errorf("error getting broken feeds: %v", err)
+	}
+	if len(brokenFeeds) == 0 {
+		fmt.Println("No broken feeds!")
+		return nil
+	}
+	for _, feed := range brokenFeeds {
+		fmt.Printf("* Name: %v\n", feed.Name)
+		fmt.Printf("* URL of Feed: %v\n", feed.Url)
+		fmt.Printf("* Consecutive Failures: %d\n", feed.ConsecutiveFailures)
+		if feed.LastErrorAt.Valid {
+			fmt.Printf("* Last Error At: %v\n", feed.LastErrorAt.Time)
+		}
+		if feed.LastError.Valid {
+			fmt.Printf("* Last Error: %v\n", feed.LastError.String)
+		}
+		if feed.DisabledAt.Valid {
+			fmt.Printf("* Disabled At: %v (re-enable with gator enablefeed %v)\n", feed.DisabledAt.Time, feed.Url)
+		} else if feed.NextFetchAt.Valid {
+			fmt.Printf("* Next Retry: %v\n", feed.NextFetchAt.Time)
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+func HandleEnableFeed(s *State, cmd Command, user database.User) error {
+	if len(cmd.Arguments) < 1 {
+		return errors.New("not enough arguments. expecting gator enablefeed <feed_url>")
+	}
+	feed, err := s.Db.GetFeedByURL(context.Background(), cmd.Arguments[0])
+	if err != nil {
+		return fmt.Errorf("couldn't get feed with url: %v", err)
+	}
+	err = s.Db.EnableFeed(context.Background(), database.EnableFeedParams{
+		UpdatedAt: time.Now(),
+		ID:        feed.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("error enabling feed: %v", err)
+	}
+	fmt.Printf("%v is enabled and will be fetched on the next agg tick\n", feed.Name)
+	return nil
+}
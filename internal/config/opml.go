@@ -0,0 +1,164 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/slajuwomi/gator/internal/database"
+	"github.com/slajuwomi/gator/internal/opml"
+)
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+func HandleImport(s *State, cmd Command, user database.User) error {
+	if len(cmd.Arguments) < 2 || cmd.Arguments[0] != "opml" {
+		return errors.New("not enough arguments. expecting gator import opml <file>")
+	}
+	file, err := os.Open(cmd.Arguments[1])
+	if err != nil {
+		return fmt.Errorf("couldn't open %v: %v", cmd.Arguments[1], err)
+	}
+	defer file.Close()
+	doc, err := opml.Parse(file)
+	if err != nil {
+		return err
+	}
+
+	var created, existing, failed int
+	for _, subscription := range doc.Subscriptions() {
+		isNew, err := importSubscription(s, user, subscription)
+		if err != nil {
+			failed++
+			fmt.Printf("Failed to import %v: %v\n", subscription.XMLURL, err)
+			continue
+		}
+		if isNew {
+			created++
+			fmt.Printf("Created feed %v\n", subscription.XMLURL)
+		} else {
+			existing++
+		}
+	}
+	fmt.Printf("Import finished: %d created, %d already existed, %d failed\n", created, existing, failed)
+	fmt.Println("New feeds will be fetched the next time gator agg runs")
+	return nil
+}
+
+// importSubscription makes sure the feed exists and that user follows it,
+// reporting whether the feed had to be created.
+func importSubscription(s *State, user database.User, subscription opml.Subscription) (bool, error) {
+	isNew, err := saveSubscription(s, user, subscription)
+	// another import or addfeed created the feed in the meantime, which
+	// aborted the transaction; going again picks up the feed it made
+	if isUniqueViolation(err) {
+		isNew, err = saveSubscription(s, user, subscription)
+	}
+	return isNew, err
+}
+
+// saveSubscription creates the feed if needed, the follow and its folder
+// together, so a failure part way doesn't leave a feed nobody follows.
+func saveSubscription(s *State, user database.User, subscription opml.Subscription) (bool, error) {
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+	qtx := s.Db.WithTx(tx)
+	isNew := false
+	feed, err := qtx.GetFeedByURL(context.Background(), subscription.XMLURL)
+	if errors.Is(err, sql.ErrNoRows) {
+		name := subscription.Title
+		if name == "" {
+			name = subscription.XMLURL
+		}
+		feed, err = qtx.CreateFeed(context.Background(), database.CreateFeedParams{
+			ID:            uuid.New(),
+			CreatedAt:     time.Now(),
+			UpdatedAt:     time.Now(),
+			LastFetchedAt: sql.NullTime{},
+			Name:          name,
+			UserID:        user.ID,
+			Url:           subscription.XMLURL})
+		isNew = err == nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error creating feed: %w", err)
+	}
+	err = qtx.EnsureFeedFollow(context.Background(), database.EnsureFeedFollowParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		UserID:    user.ID,
+		FeedID:    feed.ID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("error creating feed follow: %v", err)
+	}
+	if subscription.Folder != "" {
+		err = qtx.SetFeedFollowFolder(context.Background(), database.SetFeedFollowFolderParams{
+			Folder: sql.NullString{
+				String: subscription.Folder,
+				Valid:  true,
+			},
+			UpdatedAt: time.Now(),
+			UserID:    user.ID,
+			FeedID:    feed.ID,
+		})
+		if err != nil {
+			return false, fmt.Errorf("error saving folder: %v", err)
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		return false, fmt.Errorf("failed to commit imported feed: %v", err)
+	}
+	return isNew, nil
+}
+
+func HandleExport(s *State, cmd Command, user database.User) error {
+	if len(cmd.Arguments) < 1 || cmd.Arguments[0] != "opml" {
+		return errors.New("not enough arguments. expecting gator export opml <optional_file>")
+	}
+	follows, err := s.Db.GetFeedFollowsForUser(context.Background(), user.Name)
+	if err != nil {
+		return fmt.Errorf("error getting feeds followed by current user: %v", err)
+	}
+	var subscriptions []opml.Subscription
+	for _, follow := range follows {
+		subscriptions = append(subscriptions, opml.Subscription{
+			Title:   follow.FeedName,
+			XMLURL:  follow.FeedUrl,
+			HTMLURL: follow.FeedSiteUrl.String,
+			Folder:  follow.Folder.String,
+		})
+	}
+	doc := opml.New(fmt.Sprintf("%v's gator subscriptions", user.Name), subscriptions, time.Now())
+	if len(cmd.Arguments) < 2 {
+		return doc.Write(os.Stdout)
+	}
+	file, err := os.Create(cmd.Arguments[1])
+	if err != nil {
+		return fmt.Errorf("couldn't create %v: %v", cmd.Arguments[1], err)
+	}
+	defer file.Close()
+	err = doc.Write(file)
+	if err != nil {
+		return err
+	}
+	err = file.Close()
+	if err != nil {
+		return fmt.Errorf("couldn't write %v: %v", cmd.Arguments[1], err)
+	}
+	fmt.Printf("Exported %d feeds to %v\n", len(subscriptions), cmd.Arguments[1])
+	return nil
+}
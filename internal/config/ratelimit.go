@@ -0,0 +1,104 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+var errHostBackingOff = errors.New("host asked us to back off")
+
+// hostLimiter keeps agg polite towards hosts that serve many of our feeds:
+// at most maxConnections requests in flight per host, requests spaced out to
+// stay under the per-minute budget, and no requests while a 429 backoff lasts.
+type hostLimiter struct {
+	mu             sync.Mutex
+	maxConnections int
+	interval       time.Duration
+	hosts          map[string]*hostState
+}
+
+type hostState struct {
+	slots        chan struct{}
+	nextRequest  time.Time
+	blockedUntil time.Time
+}
+
+func newHostLimiter(maxConnections, requestsPerMinute int) *hostLimiter {
+	return &hostLimiter{
+		maxConnections: maxConnections,
+		interval:       time.Minute / time.Duration(requestsPerMinute),
+		hosts:          make(map[string]*hostState),
+	}
+}
+
+func feedHost(feedURL string) string {
+	parsedURL, err := url.Parse(feedURL)
+	if err != nil {
+		return feedURL
+	}
+	return strings.ToLower(parsedURL.Hostname())
+}
+
+func (l *hostLimiter) state(host string) *hostState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	state, ok := l.hosts[host]
+	if !ok {
+		state = &hostState{slots: make(chan struct{}, l.maxConnections)}
+		l.hosts[host] = state
+	}
+	return state
+}
+
+// acquire waits for a free connection slot and the host's next request slot.
+// A host that is backing off after a 429 is not waited on; the feed is
+// skipped with an error instead so the worker can move on.
+func (l *hostLimiter) acquire(ctx context.Context, host string) (func(), error) {
+	state := l.state(host)
+	select {
+	case state.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	release := func() { <-state.slots }
+
+	l.mu.Lock()
+	now := time.Now()
+	if state.blockedUntil.After(now) {
+		blockedUntil := state.blockedUntil
+		l.mu.Unlock()
+		release()
+		return nil, fmt.Errorf("%w: %v until %v", errHostBackingOff, host, blockedUntil.Format(time.Kitchen))
+	}
+	start := now
+	if state.nextRequest.After(start) {
+		start = state.nextRequest
+	}
+	state.nextRequest = start.Add(l.interval)
+	l.mu.Unlock()
+
+	timer := time.NewTimer(time.Until(start))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return release, nil
+	case <-ctx.Done():
+		release()
+		return nil, ctx.Err()
+	}
+}
+
+func (l *hostLimiter) backoff(host string, d time.Duration) {
+	state := l.state(host)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	until := time.Now().Add(d)
+	if until.After(state.blockedUntil) {
+		state.blockedUntil = until
+	}
+}
@@ -0,0 +1,244 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/slajuwomi/gator/internal/database"
+)
+
+// shortPostIDLength is the least of a post's UUID browse prints. Any prefix
+// at least minPostRefLength long that matches a single post is accepted.
+const (
+	shortPostIDLength = 8
+	minPostRefLength  = 4
+)
+
+var postRefPattern = regexp.MustCompile(`^[0-9a-f-]+$`)
+
+// shortPostID returns the shortest start of id, no shorter than
+// shortPostIDLength, that no other post user can refer to shares. Posts are
+// ordered by id, so only the posts on either side of it need checking.
+func shortPostID(s *State, user database.User, id uuid.UUID) (string, error) {
+	neighbours, err := s.Db.GetAdjacentPostIDsForUser(context.Background(), database.GetAdjacentPostIDsForUserParams{
+		ID:     id,
+		UserID: user.ID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error getting post ids: %v", err)
+	}
+	full := id.String()
+	length := shortPostIDLength
+	for _, neighbour := range neighbours {
+		other := neighbour.String()
+		shared := 0
+		for shared < len(full) && full[shared] == other[shared] {
+			shared++
+		}
+		if shared+1 > length {
+			length = shared + 1
+		}
+	}
+	// a dash tells nothing apart, so take the digit after it too
+	if length < len(full) && full[length-1] == '-' {
+		length++
+	}
+	if length > len(full) {
+		length = len(full)
+	}
+	return full[:length], nil
+}
+
+// postIDRange returns the lowest and highest UUIDs starting with prefix, so
+// that looking a prefix up can use the primary key. It reports false if no
+// UUID could start with prefix.
+func postIDRange(prefix string) (uuid.UUID, uuid.UUID, bool) {
+	const layout = "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx"
+	if len(prefix) > len(layout) {
+		return uuid.UUID{}, uuid.UUID{}, false
+	}
+	for i := range prefix {
+		if (prefix[i] == '-') != (layout[i] == '-') {
+			return uuid.UUID{}, uuid.UUID{}, false
+		}
+	}
+	digits := strings.ReplaceAll(prefix, "-", "")
+	low, err := uuid.Parse(digits + strings.Repeat("0", 32-len(digits)))
+	if err != nil {
+		return uuid.UUID{}, uuid.UUID{}, false
+	}
+	high, err := uuid.Parse(digits + strings.Repeat("f", 32-len(digits)))
+	if err != nil {
+		return uuid.UUID{}, uuid.UUID{}, false
+	}
+	return low, high, true
+}
+
+// resolvePost finds a post in one of the feeds user follows, or among the
+// posts they starred, by its full ID or by the start of it.
+func resolvePost(s *State, user database.User, ref string) (database.Post, error) {
+	ref = strings.ToLower(strings.TrimSpace(ref))
+	if id, err := uuid.Parse(ref); err == nil {
+		post, err := s.Db.GetPostForUser(context.Background(), database.GetPostForUserParams{
+			UserID: user.ID,
+			ID:     id,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return database.Post{}, fmt.Errorf("no post %v in the feeds you follow", ref)
+		}
+		if err != nil {
+			return database.Post{}, fmt.Errorf("error getting post: %v", err)
+		}
+		return post, nil
+	}
+	var lowID, highID uuid.UUID
+	ok := len(ref) >= minPostRefLength && postRefPattern.MatchString(ref)
+	if ok {
+		lowID, highID, ok = postIDRange(ref)
+	}
+	if !ok {
+		return database.Post{}, fmt.Errorf("%v is not a valid post id. use the id printed by gator browse", ref)
+	}
+	posts, err := s.Db.GetPostsForUserByIDRange(context.Background(), database.GetPostsForUserByIDRangeParams{
+		LowID:  lowID,
+		HighID: highID,
+		UserID: user.ID,
+	})
+	if err != nil {
+		return database.Post{}, fmt.Errorf("error getting post: %v", err)
+	}
+	switch len(posts) {
+	case 0:
+		return database.Post{}, fmt.Errorf("no post %v in the feeds you follow", ref)
+	case 1:
+		return posts[0], nil
+	}
+	return database.Post{}, fmt.Errorf("%v matches more than one post. type more of its id", ref)
+}
+
+func setPostRead(s *State, user database.User, post database.Post, read bool) error {
+	var readAt sql.NullTime
+	if read {
+		readAt = sql.NullTime{
+			Time:  time.Now(),
+			Valid: true,
+		}
+	}
+	err := s.Db.SetPostReadAt(context.Background(), database.SetPostReadAtParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		UserID:    user.ID,
+		PostID:    post.ID,
+		ReadAt:    readAt,
+	})
+	if err != nil {
+		return fmt.Errorf("error saving read state: %v", err)
+	}
+	return nil
+}
+
+func HandleRead(s *State, cmd Command, user database.User) error {
+	if len(cmd.Arguments) < 1 {
+		return errors.New("not enough arguments. expecting gator read <post>")
+	}
+	post, err := resolvePost(s, user, cmd.Arguments[0])
+	if err != nil {
+		return err
+	}
+	err = setPostRead(s, user, post, true)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Marked %v as read\n", post.Title)
+	return nil
+}
+
+func HandleUnread(s *State, cmd Command, user database.User) error {
+	if len(cmd.Arguments) < 1 {
+		return errors.New("not enough arguments. expecting gator unread <post>")
+	}
+	post, err := resolvePost(s, user, cmd.Arguments[0])
+	if err != nil {
+		return err
+	}
+	err = setPostRead(s, user, post, false)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Marked %v as unread\n", post.Title)
+	return nil
+}
+
+func HandleMarkRead(s *State, cmd Command, user database.User) error {
+	usage := errors.New("expecting gator markread --feed <feed_url> or gator markread --all, optionally followed by --before <date>")
+	var feedID uuid.NullUUID
+	var before sql.NullTime
+	all := false
+	for i := 0; i < len(cmd.Arguments); i++ {
+		switch cmd.Arguments[i] {
+		case "--all":
+			all = true
+		case "--feed":
+			if i+1 >= len(cmd.Arguments) {
+				return usage
+			}
+			i++
+			feed, err := s.Db.GetFeedByURL(context.Background(), cmd.Arguments[i])
+			if err != nil {
+				return fmt.Errorf("couldn't get feed with url: %v", err)
+			}
+			feedID = uuid.NullUUID{
+				UUID:  feed.ID,
+				Valid: true,
+			}
+		case "--before":
+			if i+1 >= len(cmd.Arguments) {
+				return usage
+			}
+			i++
+			t, err := parseBeforeDate(cmd.Arguments[i])
+			if err != nil {
+				return err
+			}
+			before = sql.NullTime{
+				Time:  t,
+				Valid: true,
+			}
+		default:
+			return usage
+		}
+	}
+	if all == feedID.Valid {
+		return usage
+	}
+	marked, err := s.Db.MarkPostsRead(context.Background(), database.MarkPostsReadParams{
+		ReadAt: time.Now(),
+		UserID: user.ID,
+		FeedID: feedID,
+		Before: before,
+	})
+	if err != nil {
+		return fmt.Errorf("error marking posts as read: %v", err)
+	}
+	fmt.Printf("Marked %d posts as read\n", marked)
+	return nil
+}
+
+// parseBeforeDate accepts a plain date, taken as midnight local time, or a
+// full RFC 3339 timestamp.
+func parseBeforeDate(value string) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("couldn't parse date %v. use 2006-01-02 or 2006-01-02T15:04:05Z07:00", value)
+}
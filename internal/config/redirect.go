@@ -0,0 +1,84 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/slajuwomi/gator/internal/database"
+)
+
+// moveFeed points feed at the URL it was permanently redirected to. If
+// another feed already has that URL, the follows, posts and fetch history
+// are merged into it and the old feed is deleted. Follows and posts the other
+// feed already has are dropped along with it, though stars and read state on
+// those posts are moved onto the other feed's copy first.
+func moveFeed(s *State, feed database.Feed, newURL string) (database.Feed, error) {
+	now := time.Now()
+	existing, err := s.Db.GetFeedByURL(context.Background(), newURL)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = s.Db.UpdateFeedURL(context.Background(), database.UpdateFeedURLParams{
+			Url:       newURL,
+			UpdatedAt: now,
+			ID:        feed.ID,
+		})
+		if err != nil {
+			return feed, fmt.Errorf("failed to update url of %v: %v", feed.Name, err)
+		}
+		fmt.Printf("%v moved permanently from %v to %v\n", feed.Name, feed.Url, newURL)
+		feed.Url = newURL
+		feed.UpdatedAt = now
+		return feed, nil
+	}
+	if err != nil {
+		return feed, fmt.Errorf("couldn't look up feed with url %v: %v", newURL, err)
+	}
+	err = s.Db.MoveFeedFollows(context.Background(), database.MoveFeedFollowsParams{
+		ToFeedID:   existing.ID,
+		UpdatedAt:  now,
+		FromFeedID: feed.ID,
+	})
+	if err != nil {
+		return feed, fmt.Errorf("failed to move follows of %v: %v", feed.Name, err)
+	}
+	err = s.Db.MovePosts(context.Background(), database.MovePostsParams{
+		ToFeedID:   existing.ID,
+		UpdatedAt:  now,
+		FromFeedID: feed.ID,
+	})
+	if err != nil {
+		return feed, fmt.Errorf("failed to move posts of %v: %v", feed.Name, err)
+	}
+	err = s.Db.MoveSavedPosts(context.Background(), database.MoveSavedPostsParams{
+		UpdatedAt:  now,
+		FromFeedID: feed.ID,
+		ToFeedID:   existing.ID,
+	})
+	if err != nil {
+		return feed, fmt.Errorf("failed to move starred posts of %v: %v", feed.Name, err)
+	}
+	err = s.Db.MovePostStates(context.Background(), database.MovePostStatesParams{
+		UpdatedAt:  now,
+		FromFeedID: feed.ID,
+		ToFeedID:   existing.ID,
+	})
+	if err != nil {
+		return feed, fmt.Errorf("failed to move read state of %v: %v", feed.Name, err)
+	}
+	err = s.Db.MoveFeedFetches(context.Background(), database.MoveFeedFetchesParams{
+		ToFeedID:   existing.ID,
+		UpdatedAt:  now,
+		FromFeedID: feed.ID,
+	})
+	if err != nil {
+		return feed, fmt.Errorf("failed to move fetch log of %v: %v", feed.Name, err)
+	}
+	err = s.Db.DeleteFeed(context.Background(), feed.ID)
+	if err != nil {
+		return feed, fmt.Errorf("failed to delete %v after merging: %v", feed.Name, err)
+	}
+	fmt.Printf("%v moved permanently from %v to %v and was merged into %v\n", feed.Name, feed.Url, newURL, existing.Name)
+	return existing, nil
+}
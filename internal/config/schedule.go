@@ -0,0 +1,108 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/slajuwomi/gator/internal/database"
+)
+
+// recentPostsForSchedule is how many of a feed's latest post dates are used to
+// estimate how often it publishes. Each date counts once, since undated items
+// fetched together all share the fetch time and would otherwise make the feed
+// look like it publishes constantly.
+const recentPostsForSchedule = 20
+
+// nextFetchInterval polls a feed about twice per observed gap between its
+// recent posts, kept within the configured bounds. Publisher hints (<ttl>,
+// sy:updatePeriod, Cache-Control max-age) can only lengthen the interval, and
+// a per-feed override set with setinterval replaces the whole calculation.
+func nextFetchInterval(cfg *Config, feed database.Feed, postDates []time.Time, hints ...time.Duration) time.Duration {
+	if feed.FetchIntervalOverrideSeconds.Valid {
+		return time.Duration(feed.FetchIntervalOverrideSeconds.Int32) * time.Second
+	}
+	interval := time.Duration(feed.FetchIntervalSeconds) * time.Second
+	if len(postDates) >= 2 {
+		// postDates are newest first
+		span := postDates[0].Sub(postDates[len(postDates)-1])
+		interval = span / time.Duration(len(postDates)-1) / 2
+	}
+	for _, hint := range hints {
+		if hint > interval {
+			interval = hint
+		}
+	}
+	if interval < cfg.minFetchInterval() {
+		interval = cfg.minFetchInterval()
+	}
+	if interval > cfg.maxFetchInterval() {
+		interval = cfg.maxFetchInterval()
+	}
+	return interval
+}
+
+func scheduleFeed(s *State, feed database.Feed, fetchResult *FetchResult) error {
+	postDates, err := s.Db.GetRecentPostDatesForFeed(context.Background(), database.GetRecentPostDatesForFeedParams{
+		FeedID: feed.ID,
+		Limit:  recentPostsForSchedule,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get recent posts for scheduling: %v", err)
+	}
+	hints := []time.Duration{fetchResult.MaxAge}
+	if fetchResult.Feed != nil {
+		hints = append(hints, fetchResult.Feed.TTL)
+	}
+	interval := nextFetchInterval(s.Cfg, feed, postDates, hints...)
+	err = s.Db.ScheduleFeed(context.Background(), database.ScheduleFeedParams{
+		NextFetchAt: sql.NullTime{
+			Time:  time.Now().Add(interval),
+			Valid: true,
+		},
+		FetchIntervalSeconds: int32(interval / time.Second),
+		UpdatedAt:            time.Now(),
+		ID:                   feed.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to schedule next fetch: %v", err)
+	}
+	return nil
+}
+
+func HandleSetInterval(s *State, cmd Command, user database.User) error {
+	if len(cmd.Arguments) < 2 {
+		return errors.New("not enough arguments. expecting gator setinterval <feed_url> <interval|auto>")
+	}
+	feed, err := s.Db.GetFeedByURL(context.Background(), cmd.Arguments[0])
+	if err != nil {
+		return fmt.Errorf("couldn't get feed with url: %v", err)
+	}
+	var override sql.NullInt32
+	if cmd.Arguments[1] != "auto" {
+		interval, err := time.ParseDuration(cmd.Arguments[1])
+		if err != nil || interval < time.Second {
+			return fmt.Errorf("interval must be a duration like 30m or 6h, or auto: %v", cmd.Arguments[1])
+		}
+		override = sql.NullInt32{
+			Int32: int32(interval / time.Second),
+			Valid: true,
+		}
+	}
+	err = s.Db.SetFeedFetchIntervalOverride(context.Background(), database.SetFeedFetchIntervalOverrideParams{
+		FetchIntervalOverrideSeconds: override,
+		UpdatedAt:                    time.Now(),
+		ID:                           feed.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("error setting fetch interval: %v", err)
+	}
+	if override.Valid {
+		fmt.Printf("%v will now be fetched every %v\n", feed.Name, time.Duration(override.Int32)*time.Second)
+	} else {
+		fmt.Printf("%v will now be fetched on an adaptive schedule\n", feed.Name)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/slajuwomi/gator/internal/database"
+)
+
+func HandleStar(s *State, cmd Command, user database.User) error {
+	if len(cmd.Arguments) < 1 {
+		return errors.New("not enough arguments. expecting gator star <post>")
+	}
+	post, err := resolvePost(s, user, cmd.Arguments[0])
+	if err != nil {
+		return err
+	}
+	err = s.Db.SavePost(context.Background(), database.SavePostParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		UserID:    user.ID,
+		PostID:    post.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("error starring post: %v", err)
+	}
+	fmt.Printf("Starred %v\n", post.Title)
+	return nil
+}
+
+func HandleUnstar(s *State, cmd Command, user database.User) error {
+	if len(cmd.Arguments) < 1 {
+		return errors.New("not enough arguments. expecting gator unstar <post>")
+	}
+	post, err := resolvePost(s, user, cmd.Arguments[0])
+	if err != nil {
+		return err
+	}
+	removed, err := s.Db.UnsavePost(context.Background(), database.UnsavePostParams{
+		UserID: user.ID,
+		PostID: post.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("error unstarring post: %v", err)
+	}
+	if removed == 0 {
+		fmt.Printf("%v was not starred\n", post.Title)
+		return nil
+	}
+	fmt.Printf("Unstarred %v\n", post.Title)
+	return nil
+}
+
+func HandleStarred(s *State, cmd Command, user database.User) error {
+	var limit int64
+	limit = 10
+	var err error
+	if len(cmd.Arguments) != 0 {
+		limit, err = strconv.ParseInt(cmd.Arguments[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("failed to convert to int: %v", err)
+		}
+	}
+	posts, err := s.Db.GetSavedPostsForUser(context.Background(), database.GetSavedPostsForUserParams{
+		UserID: user.ID,
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		return fmt.Errorf("error getting starred posts: %v", err)
+	}
+	fmt.Printf("Found %d starred posts for user %s:\n", len(posts), user.Name)
+	for _, post := range posts {
+		fmt.Printf("%s from %s\n", post.PublishedAt.Format("Mon Jan 2"), post.FeedName)
+		fmt.Printf("--- %s ---\n", post.Title)
+		shortID, err := shortPostID(s, user, post.ID)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("ID: %s\n", shortID)
+		fmt.Printf("Starred: %s\n", post.SavedAt.Format("Mon Jan 2 2006"))
+		fmt.Printf("    %v\n", post.Description)
+		fmt.Printf("Link: %s\n", post.Url)
+		fmt.Println()
+	}
+	return nil
+}
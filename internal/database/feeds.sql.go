@@ -24,7 +24,7 @@ VALUES (
     $6,
     $7
 )
-RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at
+RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, etag, last_modified, next_fetch_at, fetch_interval_seconds, fetch_interval_override_seconds, last_error, last_error_at, consecutive_failures, disabled_at, description, site_url, icon_url
 `
 
 type CreateFeedParams struct {
@@ -56,12 +56,116 @@ func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, e
 		&i.Url,
 		&i.UserID,
 		&i.LastFetchedAt,
+		&i.Etag,
+		&i.LastModified,
+		&i.NextFetchAt,
+		&i.FetchIntervalSeconds,
+		&i.FetchIntervalOverrideSeconds,
+		&i.LastError,
+		&i.LastErrorAt,
+		&i.ConsecutiveFailures,
+		&i.DisabledAt,
+		&i.Description,
+		&i.SiteUrl,
+		&i.IconUrl,
 	)
 	return i, err
 }
 
+const delayFeedFetch = `-- name: DelayFeedFetch :exec
+UPDATE feeds
+SET next_fetch_at = $1, updated_at = $2
+WHERE id = $3
+`
+
+type DelayFeedFetchParams struct {
+	NextFetchAt sql.NullTime
+	UpdatedAt   time.Time
+	ID          uuid.UUID
+}
+
+func (q *Queries) DelayFeedFetch(ctx context.Context, arg DelayFeedFetchParams) error {
+	_, err := q.db.ExecContext(ctx, delayFeedFetch, arg.NextFetchAt, arg.UpdatedAt, arg.ID)
+	return err
+}
+
+const deleteFeed = `-- name: DeleteFeed :exec
+DELETE FROM feeds
+WHERE id = $1
+`
+
+func (q *Queries) DeleteFeed(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteFeed, id)
+	return err
+}
+
+const enableFeed = `-- name: EnableFeed :exec
+UPDATE feeds
+SET disabled_at = NULL, consecutive_failures = 0, next_fetch_at = NULL, updated_at = $1
+WHERE id = $2
+`
+
+type EnableFeedParams struct {
+	UpdatedAt time.Time
+	ID        uuid.UUID
+}
+
+func (q *Queries) EnableFeed(ctx context.Context, arg EnableFeedParams) error {
+	_, err := q.db.ExecContext(ctx, enableFeed, arg.UpdatedAt, arg.ID)
+	return err
+}
+
+const getBrokenFeeds = `-- name: GetBrokenFeeds :many
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, etag, last_modified, next_fetch_at, fetch_interval_seconds, fetch_interval_override_seconds, last_error, last_error_at, consecutive_failures, disabled_at, description, site_url, icon_url FROM feeds
+WHERE consecutive_failures > 0 OR disabled_at IS NOT NULL
+ORDER BY disabled_at DESC NULLS LAST, consecutive_failures DESC
+`
+
+func (q *Queries) GetBrokenFeeds(ctx context.Context) ([]Feed, error) {
+	rows, err := q.db.QueryContext(ctx, getBrokenFeeds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Feed
+	for rows.Next() {
+		var i Feed
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Url,
+			&i.UserID,
+			&i.LastFetchedAt,
+			&i.Etag,
+			&i.LastModified,
+			&i.NextFetchAt,
+			&i.FetchIntervalSeconds,
+			&i.FetchIntervalOverrideSeconds,
+			&i.LastError,
+			&i.LastErrorAt,
+			&i.ConsecutiveFailures,
+			&i.DisabledAt,
+			&i.Description,
+			&i.SiteUrl,
+			&i.IconUrl,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getFeedByURL = `-- name: GetFeedByURL :one
-SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at FROM feeds
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, etag, last_modified, next_fetch_at, fetch_interval_seconds, fetch_interval_override_seconds, last_error, last_error_at, consecutive_failures, disabled_at, description, site_url, icon_url FROM feeds
 WHERE url = $1 LIMIT 1
 `
 
@@ -76,12 +180,24 @@ func (q *Queries) GetFeedByURL(ctx context.Context, url string) (Feed, error) {
 		&i.Url,
 		&i.UserID,
 		&i.LastFetchedAt,
+		&i.Etag,
+		&i.LastModified,
+		&i.NextFetchAt,
+		&i.FetchIntervalSeconds,
+		&i.FetchIntervalOverrideSeconds,
+		&i.LastError,
+		&i.LastErrorAt,
+		&i.ConsecutiveFailures,
+		&i.DisabledAt,
+		&i.Description,
+		&i.SiteUrl,
+		&i.IconUrl,
 	)
 	return i, err
 }
 
 const getFeeds = `-- name: GetFeeds :many
-SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at FROM feeds
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, etag, last_modified, next_fetch_at, fetch_interval_seconds, fetch_interval_override_seconds, last_error, last_error_at, consecutive_failures, disabled_at, description, site_url, icon_url FROM feeds
 `
 
 func (q *Queries) GetFeeds(ctx context.Context) ([]Feed, error) {
@@ -101,6 +217,18 @@ func (q *Queries) GetFeeds(ctx context.Context) ([]Feed, error) {
 			&i.Url,
 			&i.UserID,
 			&i.LastFetchedAt,
+			&i.Etag,
+			&i.LastModified,
+			&i.NextFetchAt,
+			&i.FetchIntervalSeconds,
+			&i.FetchIntervalOverrideSeconds,
+			&i.LastError,
+			&i.LastErrorAt,
+			&i.ConsecutiveFailures,
+			&i.DisabledAt,
+			&i.Description,
+			&i.SiteUrl,
+			&i.IconUrl,
 		); err != nil {
 			return nil, err
 		}
@@ -115,40 +243,231 @@ func (q *Queries) GetFeeds(ctx context.Context) ([]Feed, error) {
 	return items, nil
 }
 
-const getNextFeedToFetch = `-- name: GetNextFeedToFetch :one
-SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at FROM feeds
+const getNextFeedsToFetch = `-- name: GetNextFeedsToFetch :many
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, etag, last_modified, next_fetch_at, fetch_interval_seconds, fetch_interval_override_seconds, last_error, last_error_at, consecutive_failures, disabled_at, description, site_url, icon_url FROM feeds
+WHERE (next_fetch_at IS NULL OR next_fetch_at <= $1) AND disabled_at IS NULL
 ORDER BY last_fetched_at ASC NULLS FIRST
-LIMIT 1
+LIMIT $2
+FOR UPDATE SKIP LOCKED
 `
 
-func (q *Queries) GetNextFeedToFetch(ctx context.Context) (Feed, error) {
-	row := q.db.QueryRowContext(ctx, getNextFeedToFetch)
-	var i Feed
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Name,
-		&i.Url,
-		&i.UserID,
-		&i.LastFetchedAt,
-	)
-	return i, err
+type GetNextFeedsToFetchParams struct {
+	NextFetchAt sql.NullTime
+	Limit       int32
+}
+
+func (q *Queries) GetNextFeedsToFetch(ctx context.Context, arg GetNextFeedsToFetchParams) ([]Feed, error) {
+	rows, err := q.db.QueryContext(ctx, getNextFeedsToFetch, arg.NextFetchAt, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Feed
+	for rows.Next() {
+		var i Feed
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Url,
+			&i.UserID,
+			&i.LastFetchedAt,
+			&i.Etag,
+			&i.LastModified,
+			&i.NextFetchAt,
+			&i.FetchIntervalSeconds,
+			&i.FetchIntervalOverrideSeconds,
+			&i.LastError,
+			&i.LastErrorAt,
+			&i.ConsecutiveFailures,
+			&i.DisabledAt,
+			&i.Description,
+			&i.SiteUrl,
+			&i.IconUrl,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const markFeedFetched = `-- name: MarkFeedFetched :exec
 UPDATE feeds
-SET last_fetched_at = $1, updated_at = $2
-WHERE id = $3
+SET last_fetched_at = $1, next_fetch_at = $2, updated_at = $3
+WHERE id = $4
 `
 
 type MarkFeedFetchedParams struct {
 	LastFetchedAt sql.NullTime
+	NextFetchAt   sql.NullTime
 	UpdatedAt     time.Time
 	ID            uuid.UUID
 }
 
 func (q *Queries) MarkFeedFetched(ctx context.Context, arg MarkFeedFetchedParams) error {
-	_, err := q.db.ExecContext(ctx, markFeedFetched, arg.LastFetchedAt, arg.UpdatedAt, arg.ID)
+	_, err := q.db.ExecContext(ctx, markFeedFetched,
+		arg.LastFetchedAt,
+		arg.NextFetchAt,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	return err
+}
+
+const recordFeedFailure = `-- name: RecordFeedFailure :exec
+UPDATE feeds
+SET last_error = $1, last_error_at = $2, consecutive_failures = $3, next_fetch_at = $4, disabled_at = $5, updated_at = $6
+WHERE id = $7
+`
+
+type RecordFeedFailureParams struct {
+	LastError           sql.NullString
+	LastErrorAt         sql.NullTime
+	ConsecutiveFailures int32
+	NextFetchAt         sql.NullTime
+	DisabledAt          sql.NullTime
+	UpdatedAt           time.Time
+	ID                  uuid.UUID
+}
+
+func (q *Queries) RecordFeedFailure(ctx context.Context, arg RecordFeedFailureParams) error {
+	_, err := q.db.ExecContext(ctx, recordFeedFailure,
+		arg.LastError,
+		arg.LastErrorAt,
+		arg.ConsecutiveFailures,
+		arg.NextFetchAt,
+		arg.DisabledAt,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	return err
+}
+
+const recordFeedSuccess = `-- name: RecordFeedSuccess :exec
+UPDATE feeds
+SET consecutive_failures = 0, updated_at = $1
+WHERE id = $2
+`
+
+type RecordFeedSuccessParams struct {
+	UpdatedAt time.Time
+	ID        uuid.UUID
+}
+
+func (q *Queries) RecordFeedSuccess(ctx context.Context, arg RecordFeedSuccessParams) error {
+	_, err := q.db.ExecContext(ctx, recordFeedSuccess, arg.UpdatedAt, arg.ID)
+	return err
+}
+
+const scheduleFeed = `-- name: ScheduleFeed :exec
+UPDATE feeds
+SET next_fetch_at = $1, fetch_interval_seconds = $2, updated_at = $3
+WHERE id = $4
+`
+
+type ScheduleFeedParams struct {
+	NextFetchAt          sql.NullTime
+	FetchIntervalSeconds int32
+	UpdatedAt            time.Time
+	ID                   uuid.UUID
+}
+
+func (q *Queries) ScheduleFeed(ctx context.Context, arg ScheduleFeedParams) error {
+	_, err := q.db.ExecContext(ctx, scheduleFeed,
+		arg.NextFetchAt,
+		arg.FetchIntervalSeconds,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	return err
+}
+
+const setFeedFetchIntervalOverride = `-- name: SetFeedFetchIntervalOverride :exec
+UPDATE feeds
+SET fetch_interval_override_seconds = $1, next_fetch_at = NULL, updated_at = $2
+WHERE id = $3
+`
+
+type SetFeedFetchIntervalOverrideParams struct {
+	FetchIntervalOverrideSeconds sql.NullInt32
+	UpdatedAt                    time.Time
+	ID                           uuid.UUID
+}
+
+func (q *Queries) SetFeedFetchIntervalOverride(ctx context.Context, arg SetFeedFetchIntervalOverrideParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedFetchIntervalOverride, arg.FetchIntervalOverrideSeconds, arg.UpdatedAt, arg.ID)
+	return err
+}
+
+const updateFeedCacheHeaders = `-- name: UpdateFeedCacheHeaders :exec
+UPDATE feeds
+SET etag = $1, last_modified = $2, updated_at = $3
+WHERE id = $4
+`
+
+type UpdateFeedCacheHeadersParams struct {
+	Etag         sql.NullString
+	LastModified sql.NullString
+	UpdatedAt    time.Time
+	ID           uuid.UUID
+}
+
+func (q *Queries) UpdateFeedCacheHeaders(ctx context.Context, arg UpdateFeedCacheHeadersParams) error {
+	_, err := q.db.ExecContext(ctx, updateFeedCacheHeaders,
+		arg.Etag,
+		arg.LastModified,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	return err
+}
+
+const updateFeedMetadata = `-- name: UpdateFeedMetadata :exec
+UPDATE feeds
+SET description = $1, site_url = $2, icon_url = $3, updated_at = $4
+WHERE id = $5
+`
+
+type UpdateFeedMetadataParams struct {
+	Description sql.NullString
+	SiteUrl     sql.NullString
+	IconUrl     sql.NullString
+	UpdatedAt   time.Time
+	ID          uuid.UUID
+}
+
+func (q *Queries) UpdateFeedMetadata(ctx context.Context, arg UpdateFeedMetadataParams) error {
+	_, err := q.db.ExecContext(ctx, updateFeedMetadata,
+		arg.Description,
+		arg.SiteUrl,
+		arg.IconUrl,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	return err
+}
+
+const updateFeedURL = `-- name: UpdateFeedURL :exec
+UPDATE feeds
+SET url = $1, updated_at = $2
+WHERE id = $3
+`
+
+type UpdateFeedURLParams struct {
+	Url       string
+	UpdatedAt time.Time
+	ID        uuid.UUID
+}
+
+func (q *Queries) UpdateFeedURL(ctx context.Context, arg UpdateFeedURLParams) error {
+	_, err := q.db.ExecContext(ctx, updateFeedURL, arg.Url, arg.UpdatedAt, arg.ID)
 	return err
 }
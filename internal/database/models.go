@@ -12,32 +12,97 @@ import (
 )
 
 type Feed struct {
+	ID                           uuid.UUID
+	CreatedAt                    time.Time
+	UpdatedAt                    time.Time
+	Name                         string
+	Url                          string
+	UserID                       uuid.UUID
+	LastFetchedAt                sql.NullTime
+	Etag                         sql.NullString
+	LastModified                 sql.NullString
+	NextFetchAt                  sql.NullTime
+	FetchIntervalSeconds         int32
+	FetchIntervalOverrideSeconds sql.NullInt32
+	LastError                    sql.NullString
+	LastErrorAt                  sql.NullTime
+	ConsecutiveFailures          int32
+	DisabledAt                   sql.NullTime
+	Description                  sql.NullString
+	SiteUrl                      sql.NullString
+	IconUrl                      sql.NullString
+}
+
+type FeedFetch struct {
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	FeedID       uuid.UUID
+	StartedAt    time.Time
+	DurationMs   int32
+	StatusCode   sql.NullInt32
+	Bytes        int64
+	ItemsSeen    int32
+	ItemsNew     int32
+	ItemsUpdated int32
+	Error        sql.NullString
+	RedirectedTo sql.NullString
+}
+
+type FeedFollow struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    uuid.UUID
+	FeedID    uuid.UUID
+	Folder    sql.NullString
+}
+
+type Post struct {
 	ID            uuid.UUID
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
-	Name          string
+	Title         string
 	Url           string
-	UserID        uuid.UUID
-	LastFetchedAt sql.NullTime
+	Description   string
+	PublishedAt   time.Time
+	FeedID        uuid.UUID
+	Guid          string
+	ItemUpdatedAt sql.NullTime
+	ContentHash   string
+	Content       string
+	Authors       []string
+	Categories    []string
 }
 
-type FeedFollow struct {
+type PostEnclosure struct {
+	ID              uuid.UUID
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	PostID          uuid.UUID
+	Url             string
+	MimeType        string
+	Length          sql.NullInt64
+	DurationSeconds sql.NullInt32
+	Episode         sql.NullInt32
+	ThumbnailUrl    sql.NullString
+}
+
+type PostState struct {
 	ID        uuid.UUID
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	UserID    uuid.UUID
-	FeedID    uuid.UUID
+	PostID    uuid.UUID
+	ReadAt    sql.NullTime
 }
 
-type Post struct {
-	ID          uuid.UUID
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	Title       string
-	Url         string
-	Description string
-	PublishedAt time.Time
-	FeedID      uuid.UUID
+type SavedPost struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    uuid.UUID
+	PostID    uuid.UUID
 }
 
 type User struct {
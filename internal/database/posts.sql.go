@@ -11,10 +11,46 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+const adoptLegacyPostGUID = `-- name: AdoptLegacyPostGUID :exec
+UPDATE posts
+SET guid = $1
+WHERE feed_id = $2 AND url = $3 AND guid = url
+AND NOT EXISTS (SELECT 1 FROM posts AS existing WHERE existing.feed_id = $2 AND existing.guid = $1)
+`
+
+type AdoptLegacyPostGUIDParams struct {
+	Guid   string
+	FeedID uuid.UUID
+	Url    string
+}
+
+func (q *Queries) AdoptLegacyPostGUID(ctx context.Context, arg AdoptLegacyPostGUIDParams) error {
+	_, err := q.db.ExecContext(ctx, adoptLegacyPostGUID, arg.Guid, arg.FeedID, arg.Url)
+	return err
+}
+
+const adoptLegacyPostHash = `-- name: AdoptLegacyPostHash :exec
+UPDATE posts
+SET content_hash = $1
+WHERE feed_id = $2 AND guid = $3 AND content_hash = ''
+`
+
+type AdoptLegacyPostHashParams struct {
+	ContentHash string
+	FeedID      uuid.UUID
+	Guid        string
+}
+
+func (q *Queries) AdoptLegacyPostHash(ctx context.Context, arg AdoptLegacyPostHashParams) error {
+	_, err := q.db.ExecContext(ctx, adoptLegacyPostHash, arg.ContentHash, arg.FeedID, arg.Guid)
+	return err
+}
+
 const createPosts = `-- name: CreatePosts :one
-INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id)
+INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id, guid, item_updated_at, content_hash, content, authors, categories)
 VALUES (
     $1,
     $2,
@@ -23,20 +59,45 @@ VALUES (
     $5,
     $6,
     $7,
-    $8
+    $8,
+    $9,
+    $10,
+    $11,
+    $12,
+    $13,
+    $14
 )
-RETURNING id, created_at, updated_at, title, url, description, published_at, feed_id
+ON CONFLICT (feed_id, guid) DO UPDATE SET
+    updated_at = EXCLUDED.updated_at,
+    title = EXCLUDED.title,
+    url = EXCLUDED.url,
+    description = EXCLUDED.description,
+    published_at = CASE WHEN $15::boolean THEN EXCLUDED.published_at ELSE posts.published_at END,
+    item_updated_at = EXCLUDED.item_updated_at,
+    content_hash = EXCLUDED.content_hash,
+    content = EXCLUDED.content,
+    authors = EXCLUDED.authors,
+    categories = EXCLUDED.categories
+WHERE posts.content_hash <> EXCLUDED.content_hash
+RETURNING id, created_at, updated_at, title, url, description, published_at, feed_id, guid, item_updated_at, content_hash, content, authors, categories
 `
 
 type CreatePostsParams struct {
-	ID          uuid.UUID
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	Title       string
-	Url         string
-	Description string
-	PublishedAt time.Time
-	FeedID      uuid.UUID
+	ID            uuid.UUID
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	Title         string
+	Url           string
+	Description   string
+	PublishedAt   time.Time
+	FeedID        uuid.UUID
+	Guid          string
+	ItemUpdatedAt sql.NullTime
+	ContentHash   string
+	Content       string
+	Authors       []string
+	Categories    []string
+	HasDate       bool
 }
 
 func (q *Queries) CreatePosts(ctx context.Context, arg CreatePostsParams) (Post, error) {
@@ -49,6 +110,13 @@ func (q *Queries) CreatePosts(ctx context.Context, arg CreatePostsParams) (Post,
 		arg.Description,
 		arg.PublishedAt,
 		arg.FeedID,
+		arg.Guid,
+		arg.ItemUpdatedAt,
+		arg.ContentHash,
+		arg.Content,
+		pq.Array(arg.Authors),
+		pq.Array(arg.Categories),
+		arg.HasDate,
 	)
 	var i Post
 	err := row.Scan(
@@ -60,50 +128,232 @@ func (q *Queries) CreatePosts(ctx context.Context, arg CreatePostsParams) (Post,
 		&i.Description,
 		&i.PublishedAt,
 		&i.FeedID,
+		&i.Guid,
+		&i.ItemUpdatedAt,
+		&i.ContentHash,
+		&i.Content,
+		pq.Array(&i.Authors),
+		pq.Array(&i.Categories),
+	)
+	return i, err
+}
+
+const getPostForUser = `-- name: GetPostForUser :one
+SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id, posts.guid, posts.item_updated_at, posts.content_hash, posts.content, posts.authors, posts.categories FROM posts
+WHERE posts.id = $2
+AND (
+    EXISTS (SELECT 1 FROM feed_follows WHERE feed_follows.feed_id = posts.feed_id AND feed_follows.user_id = $1)
+    OR EXISTS (SELECT 1 FROM saved_posts WHERE saved_posts.post_id = posts.id AND saved_posts.user_id = $1)
+)
+`
+
+type GetPostForUserParams struct {
+	UserID uuid.UUID
+	ID     uuid.UUID
+}
+
+func (q *Queries) GetPostForUser(ctx context.Context, arg GetPostForUserParams) (Post, error) {
+	row := q.db.QueryRowContext(ctx, getPostForUser, arg.UserID, arg.ID)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Title,
+		&i.Url,
+		&i.Description,
+		&i.PublishedAt,
+		&i.FeedID,
+		&i.Guid,
+		&i.ItemUpdatedAt,
+		&i.ContentHash,
+		&i.Content,
+		pq.Array(&i.Authors),
+		pq.Array(&i.Categories),
 	)
 	return i, err
 }
 
+const getPostsForUserByIDRange = `-- name: GetPostsForUserByIDRange :many
+SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id, posts.guid, posts.item_updated_at, posts.content_hash, posts.content, posts.authors, posts.categories FROM posts
+WHERE posts.id BETWEEN $1::uuid AND $2::uuid
+AND (
+    EXISTS (SELECT 1 FROM feed_follows WHERE feed_follows.feed_id = posts.feed_id AND feed_follows.user_id = $3)
+    OR EXISTS (SELECT 1 FROM saved_posts WHERE saved_posts.post_id = posts.id AND saved_posts.user_id = $3)
+)
+ORDER BY posts.id
+LIMIT 2
+`
+
+type GetPostsForUserByIDRangeParams struct {
+	LowID  uuid.UUID
+	HighID uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) GetPostsForUserByIDRange(ctx context.Context, arg GetPostsForUserByIDRangeParams) ([]Post, error) {
+	rows, err := q.db.QueryContext(ctx, getPostsForUserByIDRange, arg.LowID, arg.HighID, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Post
+	for rows.Next() {
+		var i Post
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Url,
+			&i.Description,
+			&i.PublishedAt,
+			&i.FeedID,
+			&i.Guid,
+			&i.ItemUpdatedAt,
+			&i.ContentHash,
+			&i.Content,
+			pq.Array(&i.Authors),
+			pq.Array(&i.Categories),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAdjacentPostIDsForUser = `-- name: GetAdjacentPostIDsForUser :many
+(
+    SELECT posts.id FROM posts
+    WHERE posts.id < $1::uuid
+    AND (
+        EXISTS (SELECT 1 FROM feed_follows WHERE feed_follows.feed_id = posts.feed_id AND feed_follows.user_id = $2)
+        OR EXISTS (SELECT 1 FROM saved_posts WHERE saved_posts.post_id = posts.id AND saved_posts.user_id = $2)
+    )
+    ORDER BY posts.id DESC
+    LIMIT 1
+)
+UNION ALL
+(
+    SELECT posts.id FROM posts
+    WHERE posts.id > $1::uuid
+    AND (
+        EXISTS (SELECT 1 FROM feed_follows WHERE feed_follows.feed_id = posts.feed_id AND feed_follows.user_id = $2)
+        OR EXISTS (SELECT 1 FROM saved_posts WHERE saved_posts.post_id = posts.id AND saved_posts.user_id = $2)
+    )
+    ORDER BY posts.id ASC
+    LIMIT 1
+)
+`
+
+type GetAdjacentPostIDsForUserParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) GetAdjacentPostIDsForUser(ctx context.Context, arg GetAdjacentPostIDsForUserParams) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, getAdjacentPostIDsForUser, arg.ID, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRecentPostDatesForFeed = `-- name: GetRecentPostDatesForFeed :many
+SELECT DISTINCT published_at FROM posts
+WHERE feed_id = $1
+ORDER BY published_at DESC
+LIMIT $2
+`
+
+type GetRecentPostDatesForFeedParams struct {
+	FeedID uuid.UUID
+	Limit  int32
+}
+
+func (q *Queries) GetRecentPostDatesForFeed(ctx context.Context, arg GetRecentPostDatesForFeedParams) ([]time.Time, error) {
+	rows, err := q.db.QueryContext(ctx, getRecentPostDatesForFeed, arg.FeedID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []time.Time
+	for rows.Next() {
+		var published_at time.Time
+		if err := rows.Scan(&published_at); err != nil {
+			return nil, err
+		}
+		items = append(items, published_at)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getPostsForUser = `-- name: GetPostsForUser :many
-SELECT feed_follows.id, feed_follows.created_at, feed_follows.updated_at, feed_follows.user_id, feed_follows.feed_id, feeds.id, feeds.created_at, feeds.updated_at, name, feeds.url, feeds.user_id, last_fetched_at, posts.id, posts.created_at, posts.updated_at, title, posts.url, description, published_at, posts.feed_id, feeds.name AS feed_name FROM feed_follows
-INNER JOIN feeds ON feed_follows.feed_id = feeds.id
-INNER JOIN posts ON feed_follows.feed_id = posts.feed_id
+SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id, posts.guid, posts.item_updated_at, posts.content_hash, posts.content, posts.authors, posts.categories, feeds.name AS feed_name, post_states.read_at FROM posts
+INNER JOIN feeds ON posts.feed_id = feeds.id
+INNER JOIN feed_follows ON feed_follows.feed_id = posts.feed_id
+LEFT JOIN post_states ON post_states.post_id = posts.id AND post_states.user_id = feed_follows.user_id
 WHERE feed_follows.user_id = $1
+AND (NOT $2::bool OR post_states.read_at IS NULL)
 ORDER BY posts.published_at DESC
-LIMIT $2
+LIMIT $3
 `
 
 type GetPostsForUserParams struct {
-	UserID uuid.UUID
-	Limit  int32
+	UserID     uuid.UUID
+	UnreadOnly bool
+	Limit      int32
 }
 
 type GetPostsForUserRow struct {
 	ID            uuid.UUID
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
-	UserID        uuid.UUID
-	FeedID        uuid.UUID
-	ID_2          uuid.UUID
-	CreatedAt_2   time.Time
-	UpdatedAt_2   time.Time
-	Name          string
-	Url           string
-	UserID_2      uuid.UUID
-	LastFetchedAt sql.NullTime
-	ID_3          uuid.UUID
-	CreatedAt_3   time.Time
-	UpdatedAt_3   time.Time
 	Title         string
-	Url_2         string
+	Url           string
 	Description   string
 	PublishedAt   time.Time
-	FeedID_2      uuid.UUID
+	FeedID        uuid.UUID
+	Guid          string
+	ItemUpdatedAt sql.NullTime
+	ContentHash   string
+	Content       string
+	Authors       []string
+	Categories    []string
 	FeedName      string
+	ReadAt        sql.NullTime
 }
 
 func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams) ([]GetPostsForUserRow, error) {
-	rows, err := q.db.QueryContext(ctx, getPostsForUser, arg.UserID, arg.Limit)
+	rows, err := q.db.QueryContext(ctx, getPostsForUser, arg.UserID, arg.UnreadOnly, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -115,24 +365,19 @@ func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams
 			&i.ID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
-			&i.UserID,
-			&i.FeedID,
-			&i.ID_2,
-			&i.CreatedAt_2,
-			&i.UpdatedAt_2,
-			&i.Name,
-			&i.Url,
-			&i.UserID_2,
-			&i.LastFetchedAt,
-			&i.ID_3,
-			&i.CreatedAt_3,
-			&i.UpdatedAt_3,
 			&i.Title,
-			&i.Url_2,
+			&i.Url,
 			&i.Description,
 			&i.PublishedAt,
-			&i.FeedID_2,
+			&i.FeedID,
+			&i.Guid,
+			&i.ItemUpdatedAt,
+			&i.ContentHash,
+			&i.Content,
+			pq.Array(&i.Authors),
+			pq.Array(&i.Categories),
 			&i.FeedName,
+			&i.ReadAt,
 		); err != nil {
 			return nil, err
 		}
@@ -146,3 +391,21 @@ func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams
 	}
 	return items, nil
 }
+
+const movePosts = `-- name: MovePosts :exec
+UPDATE posts
+SET feed_id = $1, updated_at = $2
+WHERE feed_id = $3
+AND guid NOT IN (SELECT guid FROM posts WHERE feed_id = $1)
+`
+
+type MovePostsParams struct {
+	ToFeedID   uuid.UUID
+	UpdatedAt  time.Time
+	FromFeedID uuid.UUID
+}
+
+func (q *Queries) MovePosts(ctx context.Context, arg MovePostsParams) error {
+	_, err := q.db.ExecContext(ctx, movePosts, arg.ToFeedID, arg.UpdatedAt, arg.FromFeedID)
+	return err
+}
@@ -0,0 +1,156 @@
+package feedparser
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+)
+
+type atomFeed struct {
+	XMLName  xml.Name     `xml:"feed"`
+	Title    atomText     `xml:"title"`
+	Subtitle atomText     `xml:"subtitle"`
+	Icon     string       `xml:"icon"`
+	Logo     string       `xml:"logo"`
+	Links    []atomLink   `xml:"link"`
+	Authors  []atomPerson `xml:"author"`
+	Entries  []atomEntry  `xml:"entry"`
+}
+
+type atomEntry struct {
+	// media:title, media:description and media:content must match the
+	// embedded fields before the unqualified tags of the same name
+	mediaExtensions
+	ID         string         `xml:"id"`
+	Title      atomText       `xml:"title"`
+	Links      []atomLink     `xml:"link"`
+	Summary    atomText       `xml:"summary"`
+	Content    atomText       `xml:"content"`
+	Authors    []atomPerson   `xml:"author"`
+	Categories []atomCategory `xml:"category"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+}
+
+type atomLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term  string `xml:"term,attr"`
+	Label string `xml:"label,attr"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",innerxml"`
+}
+
+func (t atomText) String() string {
+	body := strings.TrimSpace(t.Body)
+	if t.Type == "xhtml" {
+		return body
+	}
+	// innerxml is raw, so decode it the way chardata would have
+	if strings.HasPrefix(body, "<![CDATA[") && strings.HasSuffix(body, "]]>") {
+		return strings.TrimSuffix(strings.TrimPrefix(body, "<![CDATA["), "]]>")
+	}
+	return html.UnescapeString(body)
+}
+
+// text is the construct as plain text. Only type="html" carries entities
+// beyond the XML escaping String already undid.
+func (t atomText) text() string {
+	if t.Type == "html" {
+		return html.UnescapeString(t.String())
+	}
+	return t.String()
+}
+
+func atomAlternateLink(links []atomLink) string {
+	for _, link := range links {
+		if link.Rel == "" || link.Rel == "alternate" {
+			return link.Href
+		}
+	}
+	if len(links) > 0 {
+		return links[0].Href
+	}
+	return ""
+}
+
+func atomNames(people []atomPerson) []string {
+	var names []string
+	for _, person := range people {
+		names = append(names, person.Name)
+	}
+	return names
+}
+
+func parseAtom(body []byte) (*Feed, error) {
+	var raw atomFeed
+	err := unmarshalXML(body, &raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal Atom XML into struct: %v", err)
+	}
+	parsed := &Feed{
+		Title:       raw.Title.text(),
+		Link:        atomAlternateLink(raw.Links),
+		Description: raw.Subtitle.text(),
+		Icon:        raw.Icon,
+	}
+	if parsed.Icon == "" {
+		parsed.Icon = raw.Logo
+	}
+	for _, entry := range raw.Entries {
+		authors := atomNames(entry.Authors)
+		if len(authors) == 0 {
+			authors = atomNames(raw.Authors)
+		}
+		var categories []string
+		for _, category := range entry.Categories {
+			if category.Label != "" {
+				categories = append(categories, category.Label)
+			} else {
+				categories = append(categories, category.Term)
+			}
+		}
+		var enclosures []Enclosure
+		for _, link := range entry.Links {
+			if link.Rel == "enclosure" {
+				length, _ := strconv.ParseInt(strings.TrimSpace(link.Length), 10, 64)
+				enclosures = append(enclosures, Enclosure{
+					URL:    link.Href,
+					Type:   link.Type,
+					Length: length,
+				})
+			}
+		}
+		description := entry.Summary.text()
+		if description == "" {
+			description = entry.Content.text()
+		}
+		parsed.Items = append(parsed.Items, Item{
+			GUID:        entry.ID,
+			Title:       entry.Title.text(),
+			Link:        atomAlternateLink(entry.Links),
+			Description: description,
+			Content:     entry.Content.String(),
+			Authors:     authors,
+			Categories:  categories,
+			Enclosures:  entry.mediaExtensions.apply(enclosures),
+			Published:   parseDate(entry.Published),
+			Updated:     parseDate(entry.Updated),
+		})
+	}
+	return parsed, nil
+}
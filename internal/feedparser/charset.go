@@ -0,0 +1,73 @@
+package feedparser
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html/charset"
+)
+
+var (
+	utf16LEBOM = []byte("\xff\xfe")
+	utf16BEBOM = []byte("\xfe\xff")
+	xmlProlog  = regexp.MustCompile(`^\s*<\?xml[^>]*\bencoding\s*=\s*["']([A-Za-z0-9._:-]+)["']`)
+)
+
+// toUTF8 transcodes body using, in order of precedence, a byte order mark,
+// the charset parameter of the Content-Type header, or the XML prolog.
+func toUTF8(body []byte, contentType string) ([]byte, error) {
+	label := ""
+	switch {
+	case bytes.HasPrefix(body, utf8BOM):
+		return bytes.TrimPrefix(body, utf8BOM), nil
+	case bytes.HasPrefix(body, utf16LEBOM):
+		label = "utf-16le"
+	case bytes.HasPrefix(body, utf16BEBOM):
+		label = "utf-16be"
+	}
+	if label == "" {
+		if _, params, err := mime.ParseMediaType(contentType); err == nil {
+			label = params["charset"]
+		}
+	}
+	if label == "" {
+		prolog := body
+		if len(prolog) > 1024 {
+			prolog = prolog[:1024]
+		}
+		if match := xmlProlog.FindSubmatch(prolog); match != nil {
+			label = string(match[1])
+		}
+	}
+	label = strings.ToLower(strings.TrimSpace(label))
+	if label == "" || label == "utf-8" || label == "utf8" {
+		return body, nil
+	}
+	encoding, name := charset.Lookup(label)
+	if encoding == nil {
+		return nil, fmt.Errorf("unsupported charset %q", label)
+	}
+	if name == "utf-8" {
+		return body, nil
+	}
+	decoded, err := encoding.NewDecoder().Bytes(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s feed: %v", name, err)
+	}
+	return bytes.TrimPrefix(decoded, utf8BOM), nil
+}
+
+// unmarshalXML expects body to already be UTF-8, so whatever encoding the
+// prolog still declares is accepted as-is instead of being rejected.
+func unmarshalXML(body []byte, v any) error {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.CharsetReader = func(label string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+	return decoder.Decode(v)
+}
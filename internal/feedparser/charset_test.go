@@ -0,0 +1,56 @@
+package feedparser
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseCharsets(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        string
+		contentType string
+		title       string
+		itemTitle   string
+	}{
+		{"ISO-8859-1 from prolog", "iso-8859-1.xml", "application/rss+xml", "Café crème", "Ça va très bien"},
+		{"ISO-8859-1 from header", "iso-8859-1.xml", "application/rss+xml; charset=ISO-8859-1", "Café crème", "Ça va très bien"},
+		{"windows-1252 from header", "windows-1252.xml", "text/xml; charset=windows-1252", "“Smart” quotes", "Price: 5€ – cheap"},
+		{"Shift_JIS from prolog", "shift_jis.xml", "", "日本語のフィード", "最初の投稿"},
+		{"Shift_JIS from header", "shift_jis.xml", "text/xml; charset=Shift_JIS", "日本語のフィード", "最初の投稿"},
+		{"header wins over prolog", "shift_jis-mislabeled.xml", "application/rss+xml; charset=Shift_JIS", "日本語のフィード", "最初の投稿"},
+		{"UTF-16 from BOM", "utf-16.xml", "", "Café crème", "日本語のフィード"},
+		{"BOM wins over header", "utf-16.xml", "text/xml; charset=ISO-8859-1", "Café crème", "日本語のフィード"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := os.ReadFile(filepath.Join("testdata", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			feed, err := Parse(bytes.NewReader(body), tt.contentType)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if feed.Title != tt.title {
+				t.Errorf("Title = %q, want %q", feed.Title, tt.title)
+			}
+			if len(feed.Items) != 1 {
+				t.Fatalf("got %d items, want 1", len(feed.Items))
+			}
+			if feed.Items[0].Title != tt.itemTitle {
+				t.Errorf("item Title = %q, want %q", feed.Items[0].Title, tt.itemTitle)
+			}
+		})
+	}
+}
+
+func TestParseUnsupportedCharset(t *testing.T) {
+	body := []byte(`<?xml version="1.0" encoding="x-made-up"?><rss version="2.0"><channel><title>x</title></channel></rss>`)
+	_, err := Parse(bytes.NewReader(body), "")
+	if err == nil {
+		t.Error("Parse succeeded, want an unsupported charset error")
+	}
+}
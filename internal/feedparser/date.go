@@ -0,0 +1,141 @@
+package feedparser
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+var dateLayouts = []string{
+	// RFC 822/1123 and the many ways feeds get it slightly wrong; leading
+	// day names are stripped before parsing so only the date part remains
+	"2 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04:05 -07:00",
+	"2 Jan 2006 15:04 -0700",
+	"2 Jan 2006 15:04:05",
+	"2 Jan 2006 15:04",
+	"2 Jan 06 15:04:05 -0700",
+	"2 Jan 06 15:04 -0700",
+	"2 Jan 06 15:04:05",
+	"2 January 2006 15:04:05 -0700",
+	"2 January 2006 15:04 -0700",
+	"2 January 2006 15:04:05",
+	"2 Jan 2006",
+	"2-Jan-06 15:04:05 -0700",
+	"2-Jan-2006 15:04:05 -0700",
+	"Jan 2 2006 15:04:05 -0700",
+	"Jan 2 2006 15:04:05",
+	"Jan 2 2006",
+	"Jan 2 15:04:05 2006",
+	"January 2 2006 15:04:05 -0700",
+	"January 2 2006",
+	// ISO 8601 / RFC 3339 and W3C-DTF as used by Dublin Core dc:date
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02T15:04:05 -0700",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05 -07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"20060102T150405Z0700",
+	"2006-01-02",
+	"2006-01",
+	"2006",
+}
+
+// named zone abbreviations that time.Parse would otherwise read as UTC
+var zoneOffsets = map[string]string{
+	"UT":   "+0000",
+	"UTC":  "+0000",
+	"GMT":  "+0000",
+	"Z":    "+0000",
+	"EST":  "-0500",
+	"EDT":  "-0400",
+	"CST":  "-0600",
+	"CDT":  "-0500",
+	"MST":  "-0700",
+	"MDT":  "-0600",
+	"PST":  "-0800",
+	"PDT":  "-0700",
+	"AKST": "-0900",
+	"AKDT": "-0800",
+	"HST":  "-1000",
+	"WET":  "+0000",
+	"WEST": "+0100",
+	"BST":  "+0100",
+	"CET":  "+0100",
+	"CEST": "+0200",
+	"MET":  "+0100",
+	"MEST": "+0200",
+	"EET":  "+0200",
+	"EEST": "+0300",
+	"MSK":  "+0300",
+	"IST":  "+0530",
+	"SGT":  "+0800",
+	"HKT":  "+0800",
+	"JST":  "+0900",
+	"KST":  "+0900",
+	"AEST": "+1000",
+	"AEDT": "+1100",
+	"ACST": "+0930",
+	"AWST": "+0800",
+	"NZST": "+1200",
+	"NZDT": "+1300",
+}
+
+var (
+	// any leading word followed by a comma, e.g. "Mon," "Lun," or "Donnerstag,"
+	leadingDayName = regexp.MustCompile(`^[^\d\s,]+\.?,\s*`)
+	// a leading word with no comma, as long as a day-of-month number follows
+	bareDayName    = regexp.MustCompile(`^\p{L}+\.?\s+(\d)`)
+	trailingZone   = regexp.MustCompile(`\s+\(?([A-Za-z]{1,5})\)?$`)
+	trailingOffset = regexp.MustCompile(`[+-]\d\d:?\d\d$`)
+	whitespace     = regexp.MustCompile(`\s+`)
+)
+
+func parseDate(s string) time.Time {
+	s = whitespace.ReplaceAllString(strings.TrimSpace(s), " ")
+	if s == "" {
+		return time.Time{}
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t
+	}
+	s = leadingDayName.ReplaceAllString(s, "")
+	if !startsWithMonth(s) {
+		s = bareDayName.ReplaceAllString(s, "$1")
+	}
+	s = strings.ReplaceAll(s, ",", "")
+	s = strings.Replace(s, "Sept ", "Sep ", 1)
+	if match := trailingZone.FindStringSubmatch(s); match != nil {
+		rest := strings.TrimSuffix(s, match[0])
+		if trailingOffset.MatchString(rest) {
+			// "+0000 GMT" carries the zone twice
+			s = rest
+		} else if offset, ok := zoneOffsets[strings.ToUpper(match[1])]; ok {
+			s = rest + " " + offset
+		}
+	}
+	for _, layout := range dateLayouts {
+		t, err := time.Parse(layout, s)
+		if err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func startsWithMonth(s string) bool {
+	word, _, _ := strings.Cut(s, " ")
+	word = strings.TrimSuffix(word, ".")
+	for month := time.January; month <= time.December; month++ {
+		if strings.EqualFold(word, month.String()) || strings.EqualFold(word, month.String()[:3]) {
+			return true
+		}
+	}
+	return strings.EqualFold(word, "Sept")
+}
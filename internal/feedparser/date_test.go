@@ -0,0 +1,43 @@
+package feedparser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  time.Time
+	}{
+		{"RFC 1123 with offset", "Mon, 02 Jan 2006 15:04:05 -0700", time.Date(2006, 1, 2, 22, 4, 5, 0, time.UTC)},
+		{"RFC 1123 with GMT", "Mon, 02 Jan 2006 15:04:05 GMT", time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{"offset and zone name together", "Mon, 02 Jan 2006 15:04:05 +0000 GMT", time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{"RFC 3339", "2006-01-02T15:04:05Z", time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{"RFC 3339 with offset", "2006-01-02T15:04:05+02:00", time.Date(2006, 1, 2, 13, 4, 5, 0, time.UTC)},
+		{"ISO 8601 without colon in offset", "2006-01-02T15:04:05+0200", time.Date(2006, 1, 2, 13, 4, 5, 0, time.UTC)},
+		{"ISO 8601 date only", "2006-01-02", time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"two-digit year", "Mon, 02 Jan 06 15:04:05 -0700", time.Date(2006, 1, 2, 22, 4, 5, 0, time.UTC)},
+		{"named zone PST", "Mon, 02 Jan 2006 15:04:05 PST", time.Date(2006, 1, 2, 23, 4, 5, 0, time.UTC)},
+		{"named zone EDT", "Tue, 04 Jul 2006 09:00:00 EDT", time.Date(2006, 7, 4, 13, 0, 0, 0, time.UTC)},
+		{"missing seconds", "Mon, 02 Jan 2006 15:04 -0700", time.Date(2006, 1, 2, 22, 4, 0, 0, time.UTC)},
+		{"missing seconds with named zone", "Mon, 02 Jan 2006 15:04 EST", time.Date(2006, 1, 2, 20, 4, 0, 0, time.UTC)},
+		{"French day name", "Lun, 02 Jan 2006 15:04:05 +0000", time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{"German day name", "Donnerstag, 05 Jan 2006 15:04:05 +0100", time.Date(2006, 1, 5, 14, 4, 5, 0, time.UTC)},
+		{"day name without comma", "Mon 02 Jan 2006 15:04:05 +0000", time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{"month first", "Jan 2 2006 15:04:05 -0700", time.Date(2006, 1, 2, 22, 4, 5, 0, time.UTC)},
+		{"Sept abbreviation", "Sat, 02 Sept 2006 15:04:05 +0000", time.Date(2006, 9, 2, 15, 4, 5, 0, time.UTC)},
+		{"extra whitespace", "  Mon,  02 Jan 2006\t15:04:05 +0000 ", time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{"empty", "", time.Time{}},
+		{"garbage", "not a date", time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseDate(tt.input)
+			if !got.Equal(tt.want) {
+				t.Errorf("parseDate(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package feedparser
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"strings"
+	"time"
+)
+
+const (
+	FormatRSS  = "rss"
+	FormatAtom = "atom"
+	FormatRDF  = "rdf"
+	FormatJSON = "json"
+)
+
+var ErrUnknownFormat = errors.New("unknown feed format")
+
+type Feed struct {
+	Format      string
+	Title       string
+	Link        string
+	Description string
+	// Icon is the image the feed uses to represent itself, if any.
+	Icon string
+	// TTL is how long the publisher asks readers to wait between polls,
+	// taken from RSS <ttl> or the syndication module's sy:updatePeriod.
+	TTL   time.Duration
+	Items []Item
+}
+
+type Item struct {
+	GUID        string
+	Title       string
+	Link        string
+	Description string
+	Content     string
+	Authors     []string
+	Categories  []string
+	Enclosures  []Enclosure
+	Published   time.Time
+	Updated     time.Time
+}
+
+type Enclosure struct {
+	URL       string
+	Type      string
+	Length    int64
+	Duration  time.Duration
+	Episode   int
+	Thumbnail string
+}
+
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+func Parse(r io.Reader, contentType string) (*Feed, error) {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read feed: %v", err)
+	}
+	body, err = toUTF8(body, contentType)
+	if err != nil {
+		return nil, err
+	}
+	format, err := sniffFormat(contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	var parsed *Feed
+	switch format {
+	case FormatJSON:
+		parsed, err = parseJSONFeed(body)
+	case FormatAtom:
+		parsed, err = parseAtom(body)
+	case FormatRDF:
+		parsed, err = parseRDF(body)
+	default:
+		parsed, err = parseRSS(body)
+	}
+	if err != nil {
+		return nil, err
+	}
+	parsed.Format = format
+	return normalize(parsed), nil
+}
+
+func sniffFormat(contentType string, body []byte) (string, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err == nil && (mediaType == "application/feed+json" || mediaType == "application/json") {
+		return FormatJSON, nil
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(body), []byte("{")) {
+		return FormatJSON, nil
+	}
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.CharsetReader = func(label string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", ErrUnknownFormat, err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "rss":
+			return FormatRSS, nil
+		case "feed":
+			return FormatAtom, nil
+		case "RDF":
+			return FormatRDF, nil
+		default:
+			return "", fmt.Errorf("%w: unexpected root element <%s>", ErrUnknownFormat, start.Name.Local)
+		}
+	}
+}
+
+func normalize(f *Feed) *Feed {
+	// Atom marks which text is HTML, so parseAtom has already decoded it;
+	// the other formats leave entities in titles and descriptions as they are
+	unescape := html.UnescapeString
+	if f.Format == FormatAtom {
+		unescape = func(s string) string { return s }
+	}
+	f.Title = strings.TrimSpace(unescape(f.Title))
+	f.Link = strings.TrimSpace(f.Link)
+	f.Icon = strings.TrimSpace(f.Icon)
+	f.Description = strings.TrimSpace(unescape(f.Description))
+	for i, item := range f.Items {
+		item.GUID = strings.TrimSpace(item.GUID)
+		item.Title = strings.TrimSpace(unescape(item.Title))
+		item.Link = strings.TrimSpace(item.Link)
+		item.Description = strings.TrimSpace(unescape(item.Description))
+		item.Content = strings.TrimSpace(item.Content)
+		item.Authors = compact(item.Authors, unescape)
+		item.Categories = compact(item.Categories, unescape)
+		f.Items[i] = item
+	}
+	return f
+}
+
+// compact trims and de-duplicates values. It never returns nil, since the
+// posts table stores authors and categories as non-null arrays.
+func compact(values []string, unescape func(string) string) []string {
+	out := []string{}
+	seen := make(map[string]bool)
+	for _, value := range values {
+		value = strings.TrimSpace(unescape(value))
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		out = append(out, value)
+	}
+	return out
+}
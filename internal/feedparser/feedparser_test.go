@@ -0,0 +1,297 @@
+package feedparser
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseFormats(t *testing.T) {
+	tests := []struct {
+		file         string
+		format       string
+		title        string
+		guid         string
+		hasUpdated   bool
+		hasEnclosure bool
+	}{
+		{"rss.xml", FormatRSS, "RSS Example", "rss-item-1", false, true},
+		{"atom.xml", FormatAtom, "Atom Example", "urn:example:entry-1", true, true},
+		{"rdf.xml", FormatRDF, "RDF Example", "https://example.com/first", false, false},
+		{"feed.json", FormatJSON, "JSON Example", "json-item-1", true, true},
+	}
+	// the format has to be sniffed from the body when the server sends no
+	// content type, a generic one, or one for the wrong format
+	contentTypes := []string{"", "text/xml; charset=utf-8", "application/rss+xml", "text/plain"}
+	for _, tt := range tests {
+		body, err := os.ReadFile(filepath.Join("testdata", tt.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, contentType := range contentTypes {
+			t.Run(tt.file+"/"+contentType, func(t *testing.T) {
+				feed, err := Parse(strings.NewReader(string(body)), contentType)
+				if err != nil {
+					t.Fatalf("Parse: %v", err)
+				}
+				if feed.Format != tt.format {
+					t.Errorf("Format = %q, want %q", feed.Format, tt.format)
+				}
+				if feed.Title != tt.title {
+					t.Errorf("Title = %q, want %q", feed.Title, tt.title)
+				}
+				if feed.Link != "https://example.com/" {
+					t.Errorf("Link = %q, want %q", feed.Link, "https://example.com/")
+				}
+				if len(feed.Items) != 1 {
+					t.Fatalf("got %d items, want 1", len(feed.Items))
+				}
+				item := feed.Items[0]
+				if item.GUID != tt.guid {
+					t.Errorf("GUID = %q, want %q", item.GUID, tt.guid)
+				}
+				if item.Title != "First & foremost" {
+					t.Errorf("Title = %q, want %q", item.Title, "First & foremost")
+				}
+				if item.Link != "https://example.com/first" {
+					t.Errorf("Link = %q, want %q", item.Link, "https://example.com/first")
+				}
+				if item.Description != "Summary of the first post" {
+					t.Errorf("Description = %q, want %q", item.Description, "Summary of the first post")
+				}
+				if item.Content != "<p>Full text</p>" {
+					t.Errorf("Content = %q, want %q", item.Content, "<p>Full text</p>")
+				}
+				if !reflect.DeepEqual(item.Authors, []string{"Jane Doe"}) {
+					t.Errorf("Authors = %q, want %q", item.Authors, []string{"Jane Doe"})
+				}
+				if !reflect.DeepEqual(item.Categories, []string{"go", "feeds"}) {
+					t.Errorf("Categories = %q, want %q", item.Categories, []string{"go", "feeds"})
+				}
+				published := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+				if !item.Published.Equal(published) {
+					t.Errorf("Published = %v, want %v", item.Published, published)
+				}
+				if tt.hasUpdated {
+					updated := time.Date(2006, 1, 3, 10, 0, 0, 0, time.UTC)
+					if !item.Updated.Equal(updated) {
+						t.Errorf("Updated = %v, want %v", item.Updated, updated)
+					}
+				}
+				if tt.hasEnclosure {
+					want := []Enclosure{{URL: "https://example.com/first.mp3", Type: "audio/mpeg", Length: 1234}}
+					if !reflect.DeepEqual(item.Enclosures, want) {
+						t.Errorf("Enclosures = %+v, want %+v", item.Enclosures, want)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestParseUnknownFormat(t *testing.T) {
+	body, err := os.ReadFile(filepath.Join("testdata", "page.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Parse(strings.NewReader(string(body)), "text/html")
+	if !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Parse error = %v, want %v", err, ErrUnknownFormat)
+	}
+}
+
+func TestParseItemWithoutAuthorsOrCategories(t *testing.T) {
+	body := `<?xml version="1.0"?>
+<rss version="2.0">
+  <channel>
+    <title>Example</title>
+    <link>https://example.com/</link>
+    <item>
+      <title>Untagged post</title>
+      <link>https://example.com/untagged</link>
+    </item>
+  </channel>
+</rss>`
+	feed, err := Parse(strings.NewReader(body), "application/rss+xml")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(feed.Items) != 1 {
+		t.Fatalf("got %d items, want 1", len(feed.Items))
+	}
+	item := feed.Items[0]
+	if item.Authors == nil || len(item.Authors) != 0 {
+		t.Errorf("Authors = %#v, want empty non-nil slice", item.Authors)
+	}
+	if item.Categories == nil || len(item.Categories) != 0 {
+		t.Errorf("Categories = %#v, want empty non-nil slice", item.Categories)
+	}
+}
+
+// elements from other namespaces share local names with the item's own
+// title, link and description, and must not replace them
+func TestParseIgnoresNamespacedItemElements(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"RSS", `<?xml version="1.0"?>
+<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom" xmlns:dc="http://purl.org/dc/elements/1.1/">
+  <channel>
+    <title>Example</title>
+    <link>https://example.com/</link>
+    <item>
+      <title>Real title</title>
+      <dc:title>Dublin Core title</dc:title>
+      <link>https://example.com/a</link>
+      <atom:link rel="self" href="https://example.com/a.xml"/>
+      <description>Real description</description>
+    </item>
+  </channel>
+</rss>`},
+		{"RDF", `<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:atom="http://www.w3.org/2005/Atom">
+  <channel rdf:about="https://example.com/">
+    <title>Example</title>
+    <link>https://example.com/</link>
+  </channel>
+  <item rdf:about="https://example.com/a">
+    <title>Real title</title>
+    <dc:title>Dublin Core title</dc:title>
+    <link>https://example.com/a</link>
+    <atom:link rel="self" href="https://example.com/a.xml"/>
+    <description>Real description</description>
+  </item>
+</rdf:RDF>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed, err := Parse(strings.NewReader(tt.body), "")
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if len(feed.Items) != 1 {
+				t.Fatalf("got %d items, want 1", len(feed.Items))
+			}
+			item := feed.Items[0]
+			if item.Title != "Real title" {
+				t.Errorf("Title = %q, want %q", item.Title, "Real title")
+			}
+			if item.Link != "https://example.com/a" {
+				t.Errorf("Link = %q, want %q", item.Link, "https://example.com/a")
+			}
+			if item.Description != "Real description" {
+				t.Errorf("Description = %q, want %q", item.Description, "Real description")
+			}
+		})
+	}
+}
+
+func TestParseAtomText(t *testing.T) {
+	body := `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title type="text">Tom &amp;amp; Jerry</title>
+  <entry>
+    <id>urn:example:entry-1</id>
+    <title type="text">a &amp;lt; b</title>
+    <link href="https://example.com/a"/>
+    <link rel="enclosure" href="https://example.com/a.mp3" type="audio/mpeg" length="unknown"/>
+    <summary type="html">AT&amp;amp;T &lt;b&gt;news&lt;/b&gt;</summary>
+  </entry>
+  <entry>
+    <id>urn:example:entry-2</id>
+    <title type="html"><![CDATA[Caf&eacute;]]></title>
+    <link href="https://example.com/b"/>
+  </entry>
+</feed>`
+	feed, err := Parse(strings.NewReader(body), "application/atom+xml")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if feed.Title != "Tom &amp; Jerry" {
+		t.Errorf("feed Title = %q, want %q", feed.Title, "Tom &amp; Jerry")
+	}
+	if len(feed.Items) != 2 {
+		t.Fatalf("got %d items, want 2", len(feed.Items))
+	}
+	tests := []struct {
+		field string
+		got   string
+		want  string
+	}{
+		{"Title", feed.Items[0].Title, "a &lt; b"},
+		{"Description", feed.Items[0].Description, "AT&T <b>news</b>"},
+		{"Title", feed.Items[1].Title, "Café"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.field, tt.got, tt.want)
+		}
+	}
+	// a length that isn't a number is dropped rather than failing the feed
+	want := []Enclosure{{URL: "https://example.com/a.mp3", Type: "audio/mpeg"}}
+	if !reflect.DeepEqual(feed.Items[0].Enclosures, want) {
+		t.Errorf("Enclosures = %+v, want %+v", feed.Items[0].Enclosures, want)
+	}
+}
+
+func TestParseIgnoresMediaTitleAndDescription(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"RSS", `<?xml version="1.0"?>
+<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/">
+  <channel>
+    <title>Example</title>
+    <item>
+      <title>Real title</title>
+      <media:title>Media title</media:title>
+      <link>https://example.com/a</link>
+      <description>Real description</description>
+      <media:description>Media description</media:description>
+      <media:content url="https://example.com/a.mp4" type="video/mp4"/>
+    </item>
+  </channel>
+</rss>`},
+		{"Atom", `<?xml version="1.0"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:media="http://search.yahoo.com/mrss/">
+  <title>Example</title>
+  <entry>
+    <id>urn:example:a</id>
+    <title>Real title</title>
+    <media:title>Media title</media:title>
+    <link href="https://example.com/a"/>
+    <summary>Real description</summary>
+    <media:description>Media description</media:description>
+    <media:content url="https://example.com/a.mp4" type="video/mp4"/>
+  </entry>
+</feed>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed, err := Parse(strings.NewReader(tt.body), "")
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if len(feed.Items) != 1 {
+				t.Fatalf("got %d items, want 1", len(feed.Items))
+			}
+			item := feed.Items[0]
+			if item.Title != "Real title" {
+				t.Errorf("Title = %q, want %q", item.Title, "Real title")
+			}
+			if item.Description != "Real description" {
+				t.Errorf("Description = %q, want %q", item.Description, "Real description")
+			}
+			want := []Enclosure{{URL: "https://example.com/a.mp4", Type: "video/mp4"}}
+			if !reflect.DeepEqual(item.Enclosures, want) {
+				t.Errorf("Enclosures = %+v, want %+v", item.Enclosures, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package feedparser
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type jsonFeed struct {
+	Version     string           `json:"version"`
+	Title       string           `json:"title"`
+	HomePageURL string           `json:"home_page_url"`
+	Description string           `json:"description"`
+	Icon        string           `json:"icon"`
+	Favicon     string           `json:"favicon"`
+	Author      *jsonFeedAuthor  `json:"author"`
+	Authors     []jsonFeedAuthor `json:"authors"`
+	Items       []jsonFeedItem   `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            jsonFeedID           `json:"id"`
+	URL           string               `json:"url"`
+	ExternalURL   string               `json:"external_url"`
+	Title         string               `json:"title"`
+	ContentHTML   string               `json:"content_html"`
+	ContentText   string               `json:"content_text"`
+	Summary       string               `json:"summary"`
+	DatePublished string               `json:"date_published"`
+	DateModified  string               `json:"date_modified"`
+	Author        *jsonFeedAuthor      `json:"author"`
+	Authors       []jsonFeedAuthor     `json:"authors"`
+	Tags          []string             `json:"tags"`
+	Attachments   []jsonFeedAttachment `json:"attachments"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonFeedAttachment struct {
+	URL               string  `json:"url"`
+	MimeType          string  `json:"mime_type"`
+	SizeInBytes       int64   `json:"size_in_bytes"`
+	DurationInSeconds float64 `json:"duration_in_seconds"`
+}
+
+// jsonFeedID accepts numeric ids as well, which some generators emit despite the spec.
+type jsonFeedID string
+
+func (id *jsonFeedID) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*id = jsonFeedID(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*id = jsonFeedID(n.String())
+	return nil
+}
+
+// jsonFeedAuthorNames prefers the 1.1 authors array over the deprecated 1.0 author object.
+func jsonFeedAuthorNames(authors []jsonFeedAuthor, author *jsonFeedAuthor) []string {
+	var names []string
+	for _, a := range authors {
+		names = append(names, a.Name)
+	}
+	if len(names) == 0 && author != nil {
+		names = append(names, author.Name)
+	}
+	return names
+}
+
+func parseJSONFeed(body []byte) (*Feed, error) {
+	var raw jsonFeed
+	err := json.Unmarshal(body, &raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON Feed into struct: %v", err)
+	}
+	parsed := &Feed{
+		Title:       raw.Title,
+		Link:        raw.HomePageURL,
+		Description: raw.Description,
+		Icon:        raw.Icon,
+	}
+	if parsed.Icon == "" {
+		parsed.Icon = raw.Favicon
+	}
+	feedAuthors := jsonFeedAuthorNames(raw.Authors, raw.Author)
+	for _, item := range raw.Items {
+		link := item.URL
+		if link == "" {
+			link = item.ExternalURL
+		}
+		content := item.ContentHTML
+		if content == "" {
+			content = item.ContentText
+		}
+		description := item.Summary
+		if description == "" {
+			description = content
+		}
+		authors := jsonFeedAuthorNames(item.Authors, item.Author)
+		if len(authors) == 0 {
+			authors = feedAuthors
+		}
+		var enclosures []Enclosure
+		for _, attachment := range item.Attachments {
+			enclosures = append(enclosures, Enclosure{
+				URL:      attachment.URL,
+				Type:     attachment.MimeType,
+				Length:   attachment.SizeInBytes,
+				Duration: time.Duration(attachment.DurationInSeconds * float64(time.Second)),
+			})
+		}
+		parsed.Items = append(parsed.Items, Item{
+			GUID:        string(item.ID),
+			Title:       item.Title,
+			Link:        link,
+			Description: description,
+			Content:     content,
+			Authors:     authors,
+			Categories:  item.Tags,
+			Enclosures:  enclosures,
+			Published:   parseDate(item.DatePublished),
+			Updated:     parseDate(item.DateModified),
+		})
+	}
+	return parsed, nil
+}
@@ -0,0 +1,142 @@
+package feedparser
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// mediaExtensions collects the Media RSS and iTunes podcast elements that can
+// appear on an RSS item or Atom entry (YouTube feeds use media:group in Atom).
+// MediaTitle and MediaDescription are only held so they are not mistaken for
+// the entry's own title and description.
+type mediaExtensions struct {
+	MediaTitle       string           `xml:"http://search.yahoo.com/mrss/ title"`
+	MediaDescription string           `xml:"http://search.yahoo.com/mrss/ description"`
+	MediaContents    []mediaContent   `xml:"http://search.yahoo.com/mrss/ content"`
+	MediaThumbnails  []mediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+	MediaGroups      []mediaGroup     `xml:"http://search.yahoo.com/mrss/ group"`
+	ITunesDuration   string           `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+	ITunesEpisode    string           `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd episode"`
+	ITunesImage      mediaThumbnail   `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+}
+
+type mediaGroup struct {
+	Contents   []mediaContent   `xml:"http://search.yahoo.com/mrss/ content"`
+	Thumbnails []mediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+}
+
+type mediaContent struct {
+	URL        string           `xml:"url,attr"`
+	Type       string           `xml:"type,attr"`
+	Medium     string           `xml:"medium,attr"`
+	FileSize   string           `xml:"fileSize,attr"`
+	Duration   string           `xml:"duration,attr"`
+	Thumbnails []mediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+}
+
+type mediaThumbnail struct {
+	URL  string `xml:"url,attr"`
+	Href string `xml:"href,attr"`
+}
+
+func (t mediaThumbnail) link() string {
+	if t.URL != "" {
+		return strings.TrimSpace(t.URL)
+	}
+	return strings.TrimSpace(t.Href)
+}
+
+// apply merges media:content entries into the plain enclosures (matching on
+// URL) and fills in the item-level thumbnail, duration and episode number.
+func (m mediaExtensions) apply(enclosures []Enclosure) []Enclosure {
+	contents := m.MediaContents
+	thumbnail := firstThumbnail(m.MediaThumbnails)
+	for _, group := range m.MediaGroups {
+		contents = append(contents, group.Contents...)
+		if thumbnail == "" {
+			thumbnail = firstThumbnail(group.Thumbnails)
+		}
+	}
+	if thumbnail == "" {
+		thumbnail = m.ITunesImage.link()
+	}
+	for _, content := range contents {
+		url := strings.TrimSpace(content.URL)
+		if url == "" {
+			continue
+		}
+		length, _ := strconv.ParseInt(strings.TrimSpace(content.FileSize), 10, 64)
+		seconds, _ := strconv.ParseFloat(strings.TrimSpace(content.Duration), 64)
+		mimeType := content.Type
+		if mimeType == "" && content.Medium != "" {
+			mimeType = content.Medium
+		}
+		found := false
+		for i, enclosure := range enclosures {
+			if enclosure.URL != url {
+				continue
+			}
+			found = true
+			if enclosure.Type == "" {
+				enclosures[i].Type = mimeType
+			}
+			if enclosure.Length == 0 {
+				enclosures[i].Length = length
+			}
+			if enclosure.Duration == 0 {
+				enclosures[i].Duration = time.Duration(seconds * float64(time.Second))
+			}
+			if enclosure.Thumbnail == "" {
+				enclosures[i].Thumbnail = firstThumbnail(content.Thumbnails)
+			}
+		}
+		if !found {
+			enclosures = append(enclosures, Enclosure{
+				URL:       url,
+				Type:      mimeType,
+				Length:    length,
+				Duration:  time.Duration(seconds * float64(time.Second)),
+				Thumbnail: firstThumbnail(content.Thumbnails),
+			})
+		}
+	}
+	duration := parseITunesDuration(m.ITunesDuration)
+	episode, _ := strconv.Atoi(strings.TrimSpace(m.ITunesEpisode))
+	for i := range enclosures {
+		if enclosures[i].Duration == 0 {
+			enclosures[i].Duration = duration
+		}
+		if enclosures[i].Thumbnail == "" {
+			enclosures[i].Thumbnail = thumbnail
+		}
+		enclosures[i].Episode = episode
+	}
+	return enclosures
+}
+
+func firstThumbnail(thumbnails []mediaThumbnail) string {
+	for _, thumbnail := range thumbnails {
+		if link := thumbnail.link(); link != "" {
+			return link
+		}
+	}
+	return ""
+}
+
+// parseITunesDuration accepts plain seconds as well as MM:SS and HH:MM:SS.
+func parseITunesDuration(s string) time.Duration {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0
+	}
+	var total float64
+	for _, part := range strings.Split(s, ":") {
+		value, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return 0
+		}
+		total = total*60 + value
+	}
+	return time.Duration(total * float64(time.Second))
+}
@@ -0,0 +1,72 @@
+package feedparser
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+type rdfFeed struct {
+	XMLName xml.Name `xml:"RDF"`
+	Channel struct {
+		Title       string `xml:"title"`
+		Link        string `xml:"link"`
+		Description string `xml:"description"`
+		Image       struct {
+			Resource string `xml:"resource,attr"`
+		} `xml:"image"`
+		syndication
+	} `xml:"channel"`
+	// RSS 1.0 puts the image itself next to the channel, which only points at it
+	Image struct {
+		URL string `xml:"url"`
+	} `xml:"image"`
+	Item []rdfItem `xml:"item"`
+}
+
+type rdfItem struct {
+	XMLName      xml.Name
+	About        string    `xml:"about,attr"`
+	Titles       []xmlText `xml:"title"`
+	Links        []xmlLink `xml:"link"`
+	Descriptions []xmlText `xml:"description"`
+	Content      string    `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	Date         string    `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Creators     []string  `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Subjects     []string  `xml:"http://purl.org/dc/elements/1.1/ subject"`
+}
+
+func parseRDF(body []byte) (*Feed, error) {
+	var raw rdfFeed
+	err := unmarshalXML(body, &raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal RDF XML into struct: %v", err)
+	}
+	parsed := &Feed{
+		Title:       raw.Channel.Title,
+		Link:        raw.Channel.Link,
+		Description: raw.Channel.Description,
+		TTL:         raw.Channel.ttl(""),
+		Icon:        raw.Image.URL,
+	}
+	if parsed.Icon == "" {
+		parsed.Icon = raw.Channel.Image.Resource
+	}
+	for _, item := range raw.Item {
+		link := firstLinkText(item.Links)
+		guid := item.About
+		if guid == "" {
+			guid = link
+		}
+		parsed.Items = append(parsed.Items, Item{
+			GUID:        guid,
+			Title:       textInSpace(item.Titles, item.XMLName.Space),
+			Link:        link,
+			Description: textInSpace(item.Descriptions, item.XMLName.Space),
+			Content:     item.Content,
+			Authors:     item.Creators,
+			Categories:  item.Subjects,
+			Published:   parseDate(item.Date),
+		})
+	}
+	return parsed, nil
+}
@@ -0,0 +1,147 @@
+package feedparser
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type rssFeed struct {
+	Channel struct {
+		Title       string    `xml:"title"`
+		Links       []xmlLink `xml:"link"`
+		Description string    `xml:"description"`
+		TTL         string    `xml:"ttl"`
+		// declared ahead of Image so itunes:image does not land in <image>
+		ITunesImage mediaThumbnail `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+		Image       struct {
+			URL string `xml:"url"`
+		} `xml:"image"`
+		syndication
+		Item []rssItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type rssItem struct {
+	XMLName xml.Name
+	// declared ahead of Titles so itunes:title is kept for the fallback
+	ITunesTitle  string         `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd title"`
+	Titles       []xmlText      `xml:"title"`
+	Links        []xmlLink      `xml:"link"`
+	Descriptions []xmlText      `xml:"description"`
+	Content      string         `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	PubDate      string         `xml:"pubDate"`
+	DCDate       string         `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Guid         string         `xml:"guid"`
+	Author       string         `xml:"author"`
+	Creators     []string       `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Categories   []string       `xml:"category"`
+	Enclosures   []rssEnclosure `xml:"enclosure"`
+	mediaExtensions
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+// xmlLink lets the channel <link> be told apart from atom:link siblings,
+// which carry their URL in href instead of the element text.
+type xmlLink struct {
+	XMLName xml.Name
+	Href    string `xml:"href,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// xmlText keeps an element's name with its text. Untagged by namespace,
+// <title> also matches dc:title, media:title and so on, so the one in the
+// item's own namespace has to be picked out afterwards.
+type xmlText struct {
+	XMLName xml.Name
+	Text    string `xml:",chardata"`
+}
+
+func textInSpace(texts []xmlText, space string) string {
+	for _, text := range texts {
+		if text.XMLName.Space == space {
+			return text.Text
+		}
+	}
+	return ""
+}
+
+func firstLinkText(links []xmlLink) string {
+	for _, link := range links {
+		if text := strings.TrimSpace(link.Text); text != "" {
+			return text
+		}
+	}
+	return ""
+}
+
+func parseRSS(body []byte) (*Feed, error) {
+	var raw rssFeed
+	err := unmarshalXML(body, &raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal RSS XML into struct: %v", err)
+	}
+	parsed := &Feed{
+		Title:       raw.Channel.Title,
+		Link:        firstLinkText(raw.Channel.Links),
+		Description: raw.Channel.Description,
+		TTL:         raw.Channel.ttl(raw.Channel.TTL),
+		Icon:        raw.Channel.Image.URL,
+	}
+	if parsed.Icon == "" {
+		parsed.Icon = raw.Channel.ITunesImage.link()
+	}
+	for _, item := range raw.Channel.Item {
+		pubDate := item.PubDate
+		if strings.TrimSpace(pubDate) == "" {
+			pubDate = item.DCDate
+		}
+		authors := item.Creators
+		if item.Author != "" {
+			authors = append([]string{rssAuthorName(item.Author)}, authors...)
+		}
+		var enclosures []Enclosure
+		for _, enclosure := range item.Enclosures {
+			length, _ := strconv.ParseInt(strings.TrimSpace(enclosure.Length), 10, 64)
+			enclosures = append(enclosures, Enclosure{
+				URL:    strings.TrimSpace(enclosure.URL),
+				Type:   enclosure.Type,
+				Length: length,
+			})
+		}
+		title := textInSpace(item.Titles, item.XMLName.Space)
+		if strings.TrimSpace(title) == "" {
+			title = item.ITunesTitle
+		}
+		parsed.Items = append(parsed.Items, Item{
+			GUID:        item.Guid,
+			Title:       title,
+			Link:        firstLinkText(item.Links),
+			Description: textInSpace(item.Descriptions, item.XMLName.Space),
+			Content:     item.Content,
+			Authors:     authors,
+			Categories:  item.Categories,
+			Enclosures:  item.mediaExtensions.apply(enclosures),
+			Published:   parseDate(pubDate),
+		})
+	}
+	return parsed, nil
+}
+
+// rssAuthorName turns the RSS 2.0 "email (Name)" form into just the name.
+func rssAuthorName(author string) string {
+	author = strings.TrimSpace(author)
+	open := strings.Index(author, "(")
+	if open >= 0 && strings.HasSuffix(author, ")") {
+		if name := strings.TrimSpace(author[open+1 : len(author)-1]); name != "" {
+			return name
+		}
+	}
+	return author
+}
@@ -0,0 +1,39 @@
+package feedparser
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// syndication is the RSS syndication module (sy:) found on RSS 1.0 and many
+// RSS 2.0 channels.
+type syndication struct {
+	UpdatePeriod    string `xml:"http://purl.org/rss/1.0/modules/syndication/ updatePeriod"`
+	UpdateFrequency string `xml:"http://purl.org/rss/1.0/modules/syndication/ updateFrequency"`
+}
+
+var updatePeriods = map[string]time.Duration{
+	"hourly":  time.Hour,
+	"daily":   24 * time.Hour,
+	"weekly":  7 * 24 * time.Hour,
+	"monthly": 30 * 24 * time.Hour,
+	"yearly":  365 * 24 * time.Hour,
+}
+
+// ttl prefers an RSS <ttl> given in minutes and otherwise spreads the
+// sy:updatePeriod over sy:updateFrequency updates.
+func (s syndication) ttl(rssTTL string) time.Duration {
+	if minutes, err := strconv.Atoi(strings.TrimSpace(rssTTL)); err == nil && minutes > 0 {
+		return time.Duration(minutes) * time.Minute
+	}
+	period, ok := updatePeriods[strings.ToLower(strings.TrimSpace(s.UpdatePeriod))]
+	if !ok {
+		return 0
+	}
+	frequency, err := strconv.Atoi(strings.TrimSpace(s.UpdateFrequency))
+	if err != nil || frequency < 1 {
+		frequency = 1
+	}
+	return period / time.Duration(frequency)
+}
@@ -0,0 +1,193 @@
+package opml
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html/charset"
+)
+
+type Document struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Head    Head     `xml:"head"`
+	Body    Body     `xml:"body"`
+}
+
+type Head struct {
+	Title       string `xml:"title,omitempty"`
+	DateCreated string `xml:"dateCreated,omitempty"`
+}
+
+type Body struct {
+	Outlines []Outline `xml:"outline"`
+}
+
+type Outline struct {
+	Text     string    `xml:"text,attr"`
+	Title    string    `xml:"title,attr,omitempty"`
+	Type     string    `xml:"type,attr,omitempty"`
+	XMLURL   string    `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL  string    `xml:"htmlUrl,attr,omitempty"`
+	Category string    `xml:"category,attr,omitempty"`
+	Outlines []Outline `xml:"outline"`
+}
+
+// UnmarshalXML reads attribute names case-insensitively, since exporters
+// disagree on xmlUrl vs xmlURL vs xmlurl.
+func (o *Outline) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for _, attr := range start.Attr {
+		switch strings.ToLower(attr.Name.Local) {
+		case "text":
+			o.Text = attr.Value
+		case "title":
+			o.Title = attr.Value
+		case "type":
+			o.Type = attr.Value
+		case "xmlurl":
+			o.XMLURL = strings.TrimSpace(attr.Value)
+		case "htmlurl":
+			o.HTMLURL = strings.TrimSpace(attr.Value)
+		case "category":
+			o.Category = attr.Value
+		}
+	}
+	var children struct {
+		Outlines []Outline `xml:"outline"`
+	}
+	err := d.DecodeElement(&children, &start)
+	if err != nil {
+		return err
+	}
+	o.Outlines = children.Outlines
+	return nil
+}
+
+func (o Outline) name() string {
+	if o.Title != "" {
+		return strings.TrimSpace(o.Title)
+	}
+	return strings.TrimSpace(o.Text)
+}
+
+// Subscription is a feed outline along with the folder it was found in.
+// Nested folders are joined with "/".
+type Subscription struct {
+	Title   string
+	XMLURL  string
+	HTMLURL string
+	Folder  string
+}
+
+func Parse(r io.Reader) (*Document, error) {
+	decoder := xml.NewDecoder(r)
+	decoder.CharsetReader = charset.NewReaderLabel
+	var doc Document
+	err := decoder.Decode(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse OPML: %v", err)
+	}
+	return &doc, nil
+}
+
+// Subscriptions walks every outline in the document and returns the ones
+// that point at a feed. Outlines without an xmlUrl are treated as folders.
+func (d *Document) Subscriptions() []Subscription {
+	var subscriptions []Subscription
+	var walk func(outlines []Outline, folder string)
+	walk = func(outlines []Outline, folder string) {
+		for _, outline := range outlines {
+			if outline.XMLURL == "" {
+				walk(outline.Outlines, joinFolder(folder, outline.name()))
+				continue
+			}
+			subscriptionFolder := folder
+			// OPML 2.0 can also file a feed with a category path like "/Tech/Go"
+			if subscriptionFolder == "" && outline.Category != "" {
+				category, _, _ := strings.Cut(outline.Category, ",")
+				subscriptionFolder = strings.Trim(strings.TrimSpace(category), "/")
+			}
+			subscriptions = append(subscriptions, Subscription{
+				Title:   outline.name(),
+				XMLURL:  outline.XMLURL,
+				HTMLURL: outline.HTMLURL,
+				Folder:  subscriptionFolder,
+			})
+			walk(outline.Outlines, folder)
+		}
+	}
+	walk(d.Body.Outlines, "")
+	return subscriptions
+}
+
+// New builds an OPML 2.0 document from subscriptions, nesting each one
+// under outlines for its folder path. Folders and feeds keep the order they
+// first appear in.
+func New(title string, subscriptions []Subscription, created time.Time) *Document {
+	doc := &Document{
+		Version: "2.0",
+		Head: Head{
+			Title:       title,
+			DateCreated: created.Format(time.RFC1123Z),
+		},
+	}
+	for _, subscription := range subscriptions {
+		outlines := &doc.Body.Outlines
+		if subscription.Folder != "" {
+			for _, name := range strings.Split(subscription.Folder, "/") {
+				outlines = folderOutlines(outlines, name)
+			}
+		}
+		*outlines = append(*outlines, Outline{
+			Text:    subscription.Title,
+			Title:   subscription.Title,
+			Type:    "rss",
+			XMLURL:  subscription.XMLURL,
+			HTMLURL: subscription.HTMLURL,
+		})
+	}
+	return doc
+}
+
+// folderOutlines returns the children of the folder called name among
+// outlines, adding the folder if it isn't there yet.
+func folderOutlines(outlines *[]Outline, name string) *[]Outline {
+	for i := range *outlines {
+		if (*outlines)[i].XMLURL == "" && (*outlines)[i].Text == name {
+			return &(*outlines)[i].Outlines
+		}
+	}
+	*outlines = append(*outlines, Outline{
+		Text:  name,
+		Title: name,
+	})
+	return &(*outlines)[len(*outlines)-1].Outlines
+}
+
+func (d *Document) Write(w io.Writer) error {
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	err = encoder.Encode(d)
+	if err != nil {
+		return fmt.Errorf("failed to write OPML: %v", err)
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+func joinFolder(parent, name string) string {
+	if name == "" {
+		return parent
+	}
+	if parent == "" {
+		return name
+	}
+	return parent + "/" + name
+}
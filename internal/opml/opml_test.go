@@ -0,0 +1,101 @@
+package opml
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSubscriptions(t *testing.T) {
+	body := `<?xml version="1.0" encoding="UTF-8"?>
+<opml version="2.0">
+  <head><title>Subscriptions</title></head>
+  <body>
+    <outline text="Top level" type="rss" xmlUrl="https://example.com/top.xml" htmlUrl="https://example.com/"/>
+    <outline text="Tech">
+      <outline text="Go" title="Go blog" type="rss" xmlURL=" https://go.dev/blog/feed.atom "/>
+      <outline text="Databases">
+        <outline text="Postgres" type="rss" xmlurl="https://postgres.example/rss" HTMLURL="https://postgres.example/"/>
+      </outline>
+    </outline>
+    <outline text="Empty folder"/>
+    <outline text="Filed by category" type="rss" xmlUrl="https://example.com/category.xml" category="/A/B,/C"/>
+    <outline text="Folder wins">
+      <outline text="Both" type="rss" xmlUrl="https://example.com/both.xml" category="/Ignored"/>
+    </outline>
+  </body>
+</opml>`
+	doc, err := Parse(strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := []Subscription{
+		{Title: "Top level", XMLURL: "https://example.com/top.xml", HTMLURL: "https://example.com/"},
+		{Title: "Go blog", XMLURL: "https://go.dev/blog/feed.atom", Folder: "Tech"},
+		{Title: "Postgres", XMLURL: "https://postgres.example/rss", HTMLURL: "https://postgres.example/", Folder: "Tech/Databases"},
+		{Title: "Filed by category", XMLURL: "https://example.com/category.xml", Folder: "A/B"},
+		{Title: "Both", XMLURL: "https://example.com/both.xml", Folder: "Folder wins"},
+	}
+	got := doc.Subscriptions()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Subscriptions() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	_, err := Parse(strings.NewReader("<opml><body><outline"))
+	if err == nil {
+		t.Error("Parse succeeded, want an error")
+	}
+}
+
+func TestNewRoundTrip(t *testing.T) {
+	subscriptions := []Subscription{
+		{Title: "A", XMLURL: "https://a.example/feed", HTMLURL: "https://a.example/"},
+		{Title: "B", XMLURL: "https://b.example/feed", Folder: "Tech/Go"},
+		{Title: "C", XMLURL: "https://c.example/feed", Folder: "News"},
+		{Title: "D", XMLURL: "https://d.example/feed", Folder: "Tech"},
+		{Title: "E", XMLURL: "https://e.example/feed", Folder: "Tech/Go"},
+		{Title: "F", XMLURL: "https://f.example/feed"},
+		{Title: "G", XMLURL: "https://g.example/feed", Folder: "Tech/Rust/Async"},
+	}
+	doc := New("Round trip", subscriptions, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	var buf bytes.Buffer
+	err := doc.Write(&buf)
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	parsed, err := Parse(&buf)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if parsed.Head.Title != "Round trip" {
+		t.Errorf("Head.Title = %q, want %q", parsed.Head.Title, "Round trip")
+	}
+	// folders are written where they first appear, holding every feed filed
+	// under them, so feeds come back grouped by folder in first-seen order
+	want := []Subscription{
+		subscriptions[0],
+		subscriptions[1],
+		subscriptions[4],
+		subscriptions[3],
+		subscriptions[6],
+		subscriptions[2],
+		subscriptions[5],
+	}
+	got := parsed.Subscriptions()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Subscriptions() =\n%+v\nwant\n%+v", got, want)
+	}
+	var folders []string
+	for _, outline := range parsed.Body.Outlines {
+		if outline.XMLURL == "" {
+			folders = append(folders, outline.Text)
+		}
+	}
+	if !reflect.DeepEqual(folders, []string{"Tech", "News"}) {
+		t.Errorf("top level folders = %q, want %q", folders, []string{"Tech", "News"})
+	}
+}
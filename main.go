@@ -28,6 +28,7 @@ func main() {
 	}
 	dbQueries := database.New(db)
 	newState.Db = dbQueries
+	newState.Conn = db
 	commands.RegisterNewCommand("login", config.HandlerLogin)
 	commands.RegisterNewCommand("register", config.HandlerRegister)
 	commands.RegisterNewCommand("reset", config.HandleReset)
@@ -38,7 +39,19 @@ func main() {
 	commands.RegisterNewCommand("follow", config.MiddlewareLoggedIn(config.HandleFeedFollow))
 	commands.RegisterNewCommand("following", config.MiddlewareLoggedIn(config.HandleFollowing))
 	commands.RegisterNewCommand("unfollow", config.MiddlewareLoggedIn(config.HandleUnfollow))
+	commands.RegisterNewCommand("import", config.MiddlewareLoggedIn(config.HandleImport))
+	commands.RegisterNewCommand("export", config.MiddlewareLoggedIn(config.HandleExport))
+	commands.RegisterNewCommand("setinterval", config.MiddlewareLoggedIn(config.HandleSetInterval))
+	commands.RegisterNewCommand("enablefeed", config.MiddlewareLoggedIn(config.HandleEnableFeed))
+	commands.RegisterNewCommand("fetchlog", config.MiddlewareLoggedIn(config.HandleFetchLog))
 	commands.RegisterNewCommand("browse", config.MiddlewareLoggedIn(config.HandleBrowse))
+	commands.RegisterNewCommand("read", config.MiddlewareLoggedIn(config.HandleRead))
+	commands.RegisterNewCommand("unread", config.MiddlewareLoggedIn(config.HandleUnread))
+	commands.RegisterNewCommand("markread", config.MiddlewareLoggedIn(config.HandleMarkRead))
+	commands.RegisterNewCommand("star", config.MiddlewareLoggedIn(config.HandleStar))
+	commands.RegisterNewCommand("unstar", config.MiddlewareLoggedIn(config.HandleUnstar))
+	commands.RegisterNewCommand("starred", config.MiddlewareLoggedIn(config.HandleStarred))
+	commands.RegisterNewCommand("episodes", config.MiddlewareLoggedIn(config.HandleEpisodes))
 	if len(os.Args) < 2 {
 		fmt.Println("need at least two arguments")
 		os.Exit(1)
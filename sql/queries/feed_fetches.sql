@@ -0,0 +1,29 @@
+-- name: CreateFeedFetch :one
+INSERT INTO feed_fetches (id, created_at, updated_at, feed_id, started_at, duration_ms, status_code, bytes, items_seen, items_new, items_updated, error, redirected_to)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10,
+    $11,
+    $12,
+    $13
+)
+RETURNING *;
+
+-- name: GetFeedFetchesForFeed :many
+SELECT * FROM feed_fetches
+WHERE feed_id = $1
+ORDER BY started_at DESC
+LIMIT $2;
+
+-- name: MoveFeedFetches :exec
+UPDATE feed_fetches
+SET feed_id = sqlc.arg(to_feed_id), updated_at = sqlc.arg(updated_at)
+WHERE feed_id = sqlc.arg(from_feed_id);
\ No newline at end of file
@@ -23,12 +23,36 @@ SELECT
     feed_follows.*,
     feeds.name AS feed_name,
     users.name AS user_name,
-    feeds.url AS feed_url     
+    feeds.url AS feed_url,
+    feeds.site_url AS feed_site_url
 FROM feed_follows
 INNER JOIN users on feed_follows.user_id = users.id
 INNER JOIN feeds on feed_follows.feed_id = feeds.id
-WHERE users.name = $1;
+WHERE users.name = $1
+ORDER BY feed_follows.folder NULLS FIRST, feeds.name;
 
 -- name: DeleteFeedFollow :exec
 DELETE FROM feed_follows 
-WHERE user_id = $1 AND feed_id = $2;
\ No newline at end of file
+WHERE user_id = $1 AND feed_id = $2;
+
+-- name: MoveFeedFollows :exec
+UPDATE feed_follows
+SET feed_id = sqlc.arg(to_feed_id), updated_at = sqlc.arg(updated_at)
+WHERE feed_id = sqlc.arg(from_feed_id)
+AND user_id NOT IN (SELECT user_id FROM feed_follows WHERE feed_id = sqlc.arg(to_feed_id));
+
+-- name: SetFeedFollowFolder :exec
+UPDATE feed_follows
+SET folder = $1, updated_at = $2
+WHERE user_id = $3 AND feed_id = $4;
+
+-- name: EnsureFeedFollow :exec
+INSERT INTO feed_follows (id, created_at, updated_at, user_id, feed_id)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+)
+ON CONFLICT (user_id, feed_id) DO NOTHING;
\ No newline at end of file
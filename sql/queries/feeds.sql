@@ -14,16 +14,72 @@ RETURNING *;
 -- name: GetFeeds :many
 SELECT * FROM feeds;
 
+-- name: GetBrokenFeeds :many
+SELECT * FROM feeds
+WHERE consecutive_failures > 0 OR disabled_at IS NOT NULL
+ORDER BY disabled_at DESC NULLS LAST, consecutive_failures DESC;
+
 -- name: GetFeedByURL :one
 SELECT * FROM feeds
 WHERE url = $1 LIMIT 1;
 
 -- name: MarkFeedFetched :exec
 UPDATE feeds
-SET last_fetched_at = $1, updated_at = $2
-WHERE id = $3;
+SET last_fetched_at = $1, next_fetch_at = $2, updated_at = $3
+WHERE id = $4;
 
--- name: GetNextFeedToFetch :one
+-- name: GetNextFeedsToFetch :many
 SELECT * FROM feeds
+WHERE (next_fetch_at IS NULL OR next_fetch_at <= $1) AND disabled_at IS NULL
 ORDER BY last_fetched_at ASC NULLS FIRST
-LIMIT 1;
\ No newline at end of file
+LIMIT $2
+FOR UPDATE SKIP LOCKED;
+
+-- name: UpdateFeedCacheHeaders :exec
+UPDATE feeds
+SET etag = $1, last_modified = $2, updated_at = $3
+WHERE id = $4;
+
+-- name: UpdateFeedMetadata :exec
+UPDATE feeds
+SET description = $1, site_url = $2, icon_url = $3, updated_at = $4
+WHERE id = $5;
+
+-- name: ScheduleFeed :exec
+UPDATE feeds
+SET next_fetch_at = $1, fetch_interval_seconds = $2, updated_at = $3
+WHERE id = $4;
+
+-- name: SetFeedFetchIntervalOverride :exec
+UPDATE feeds
+SET fetch_interval_override_seconds = $1, next_fetch_at = NULL, updated_at = $2
+WHERE id = $3;
+
+-- name: DelayFeedFetch :exec
+UPDATE feeds
+SET next_fetch_at = $1, updated_at = $2
+WHERE id = $3;
+
+-- name: RecordFeedFailure :exec
+UPDATE feeds
+SET last_error = $1, last_error_at = $2, consecutive_failures = $3, next_fetch_at = $4, disabled_at = $5, updated_at = $6
+WHERE id = $7;
+
+-- name: RecordFeedSuccess :exec
+UPDATE feeds
+SET consecutive_failures = 0, updated_at = $1
+WHERE id = $2;
+
+-- name: EnableFeed :exec
+UPDATE feeds
+SET disabled_at = NULL, consecutive_failures = 0, next_fetch_at = NULL, updated_at = $1
+WHERE id = $2;
+
+-- name: UpdateFeedURL :exec
+UPDATE feeds
+SET url = $1, updated_at = $2
+WHERE id = $3;
+
+-- name: DeleteFeed :exec
+DELETE FROM feeds
+WHERE id = $1;
\ No newline at end of file
@@ -0,0 +1,40 @@
+-- name: CreatePostEnclosure :one
+INSERT INTO post_enclosures (id, created_at, updated_at, post_id, url, mime_type, length, duration_seconds, episode, thumbnail_url)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10
+)
+ON CONFLICT (post_id, url) DO UPDATE SET
+    updated_at = EXCLUDED.updated_at,
+    mime_type = EXCLUDED.mime_type,
+    length = EXCLUDED.length,
+    duration_seconds = EXCLUDED.duration_seconds,
+    episode = EXCLUDED.episode,
+    thumbnail_url = EXCLUDED.thumbnail_url
+RETURNING *;
+
+-- name: DeleteStalePostEnclosures :exec
+DELETE FROM post_enclosures
+WHERE post_id = sqlc.arg(post_id) AND NOT (url = ANY(sqlc.arg(urls)::text[]));
+
+-- name: GetEpisodesForUser :many
+SELECT
+    post_enclosures.*,
+    posts.title AS post_title,
+    posts.published_at,
+    feeds.name AS feed_name
+FROM post_enclosures
+INNER JOIN posts ON post_enclosures.post_id = posts.id
+INNER JOIN feeds ON posts.feed_id = feeds.id
+INNER JOIN feed_follows ON feed_follows.feed_id = feeds.id
+WHERE feed_follows.user_id = $1
+ORDER BY posts.published_at DESC
+LIMIT $2;
\ No newline at end of file
@@ -0,0 +1,36 @@
+-- name: SetPostReadAt :exec
+INSERT INTO post_states (id, created_at, updated_at, user_id, post_id, read_at)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6
+)
+ON CONFLICT (user_id, post_id) DO UPDATE SET
+    read_at = EXCLUDED.read_at,
+    updated_at = EXCLUDED.updated_at;
+
+-- name: MarkPostsRead :execrows
+INSERT INTO post_states (id, created_at, updated_at, user_id, post_id, read_at)
+SELECT gen_random_uuid(), sqlc.arg(read_at), sqlc.arg(read_at), feed_follows.user_id, posts.id, sqlc.arg(read_at)
+FROM posts
+INNER JOIN feed_follows ON feed_follows.feed_id = posts.feed_id
+WHERE feed_follows.user_id = sqlc.arg(user_id)
+AND (sqlc.narg(feed_id)::uuid IS NULL OR posts.feed_id = sqlc.narg(feed_id))
+AND (sqlc.narg(before)::timestamp IS NULL OR posts.published_at < sqlc.narg(before))
+ON CONFLICT (user_id, post_id) DO UPDATE SET
+    read_at = EXCLUDED.read_at,
+    updated_at = EXCLUDED.updated_at
+WHERE post_states.read_at IS NULL;
+
+-- name: MovePostStates :exec
+UPDATE post_states
+SET post_id = target.id, updated_at = sqlc.arg(updated_at)
+FROM posts AS old, posts AS target
+WHERE post_states.post_id = old.id
+AND old.feed_id = sqlc.arg(from_feed_id)
+AND target.feed_id = sqlc.arg(to_feed_id)
+AND target.guid = old.guid
+AND NOT EXISTS (SELECT 1 FROM post_states AS existing WHERE existing.user_id = post_states.user_id AND existing.post_id = target.id);
\ No newline at end of file
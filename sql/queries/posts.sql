@@ -1,21 +1,105 @@
 -- name: CreatePosts :one
-INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id)
+INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id, guid, item_updated_at, content_hash, content, authors, categories)
 VALUES (
-    $1,
-    $2,
-    $3,
-    $4,
-    $5,
-    $6,
-    $7,
-    $8
+    sqlc.arg(id),
+    sqlc.arg(created_at),
+    sqlc.arg(updated_at),
+    sqlc.arg(title),
+    sqlc.arg(url),
+    sqlc.arg(description),
+    sqlc.arg(published_at),
+    sqlc.arg(feed_id),
+    sqlc.arg(guid),
+    sqlc.arg(item_updated_at),
+    sqlc.arg(content_hash),
+    sqlc.arg(content),
+    sqlc.arg(authors),
+    sqlc.arg(categories)
 )
+ON CONFLICT (feed_id, guid) DO UPDATE SET
+    updated_at = EXCLUDED.updated_at,
+    title = EXCLUDED.title,
+    url = EXCLUDED.url,
+    description = EXCLUDED.description,
+    published_at = CASE WHEN sqlc.arg(has_date)::boolean THEN EXCLUDED.published_at ELSE posts.published_at END,
+    item_updated_at = EXCLUDED.item_updated_at,
+    content_hash = EXCLUDED.content_hash,
+    content = EXCLUDED.content,
+    authors = EXCLUDED.authors,
+    categories = EXCLUDED.categories
+WHERE posts.content_hash <> EXCLUDED.content_hash
 RETURNING *;
 
 -- name: GetPostsForUser :many
-SELECT *, feeds.name AS feed_name FROM feed_follows
-INNER JOIN feeds ON feed_follows.feed_id = feeds.id
-INNER JOIN posts ON feed_follows.feed_id = posts.feed_id
-WHERE feed_follows.user_id = $1
+SELECT posts.*, feeds.name AS feed_name, post_states.read_at FROM posts
+INNER JOIN feeds ON posts.feed_id = feeds.id
+INNER JOIN feed_follows ON feed_follows.feed_id = posts.feed_id
+LEFT JOIN post_states ON post_states.post_id = posts.id AND post_states.user_id = feed_follows.user_id
+WHERE feed_follows.user_id = sqlc.arg(user_id)
+AND (NOT sqlc.arg(unread_only)::bool OR post_states.read_at IS NULL)
 ORDER BY posts.published_at DESC
-LIMIT $2;
\ No newline at end of file
+LIMIT sqlc.arg('limit');
+
+-- name: GetPostForUser :one
+SELECT posts.* FROM posts
+WHERE posts.id = $2
+AND (
+    EXISTS (SELECT 1 FROM feed_follows WHERE feed_follows.feed_id = posts.feed_id AND feed_follows.user_id = $1)
+    OR EXISTS (SELECT 1 FROM saved_posts WHERE saved_posts.post_id = posts.id AND saved_posts.user_id = $1)
+);
+
+-- name: GetPostsForUserByIDRange :many
+SELECT posts.* FROM posts
+WHERE posts.id BETWEEN sqlc.arg(low_id)::uuid AND sqlc.arg(high_id)::uuid
+AND (
+    EXISTS (SELECT 1 FROM feed_follows WHERE feed_follows.feed_id = posts.feed_id AND feed_follows.user_id = sqlc.arg(user_id))
+    OR EXISTS (SELECT 1 FROM saved_posts WHERE saved_posts.post_id = posts.id AND saved_posts.user_id = sqlc.arg(user_id))
+)
+ORDER BY posts.id
+LIMIT 2;
+
+-- name: GetAdjacentPostIDsForUser :many
+(
+    SELECT posts.id FROM posts
+    WHERE posts.id < sqlc.arg(id)::uuid
+    AND (
+        EXISTS (SELECT 1 FROM feed_follows WHERE feed_follows.feed_id = posts.feed_id AND feed_follows.user_id = sqlc.arg(user_id))
+        OR EXISTS (SELECT 1 FROM saved_posts WHERE saved_posts.post_id = posts.id AND saved_posts.user_id = sqlc.arg(user_id))
+    )
+    ORDER BY posts.id DESC
+    LIMIT 1
+)
+UNION ALL
+(
+    SELECT posts.id FROM posts
+    WHERE posts.id > sqlc.arg(id)::uuid
+    AND (
+        EXISTS (SELECT 1 FROM feed_follows WHERE feed_follows.feed_id = posts.feed_id AND feed_follows.user_id = sqlc.arg(user_id))
+        OR EXISTS (SELECT 1 FROM saved_posts WHERE saved_posts.post_id = posts.id AND saved_posts.user_id = sqlc.arg(user_id))
+    )
+    ORDER BY posts.id ASC
+    LIMIT 1
+);
+
+-- name: GetRecentPostDatesForFeed :many
+SELECT DISTINCT published_at FROM posts
+WHERE feed_id = $1
+ORDER BY published_at DESC
+LIMIT $2;
+
+-- name: MovePosts :exec
+UPDATE posts
+SET feed_id = sqlc.arg(to_feed_id), updated_at = sqlc.arg(updated_at)
+WHERE feed_id = sqlc.arg(from_feed_id)
+AND guid NOT IN (SELECT guid FROM posts WHERE feed_id = sqlc.arg(to_feed_id));
+
+-- name: AdoptLegacyPostGUID :exec
+UPDATE posts
+SET guid = sqlc.arg(guid)
+WHERE feed_id = sqlc.arg(feed_id) AND url = sqlc.arg(url) AND guid = url
+AND NOT EXISTS (SELECT 1 FROM posts AS existing WHERE existing.feed_id = sqlc.arg(feed_id) AND existing.guid = sqlc.arg(guid));
+
+-- name: AdoptLegacyPostHash :exec
+UPDATE posts
+SET content_hash = $1
+WHERE feed_id = $2 AND guid = $3 AND content_hash = '';
\ No newline at end of file
@@ -0,0 +1,32 @@
+-- name: SavePost :exec
+INSERT INTO saved_posts (id, created_at, updated_at, user_id, post_id)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+)
+ON CONFLICT (user_id, post_id) DO NOTHING;
+
+-- name: UnsavePost :execrows
+DELETE FROM saved_posts
+WHERE user_id = $1 AND post_id = $2;
+
+-- name: GetSavedPostsForUser :many
+SELECT posts.*, feeds.name AS feed_name, saved_posts.created_at AS saved_at FROM saved_posts
+INNER JOIN posts ON saved_posts.post_id = posts.id
+INNER JOIN feeds ON posts.feed_id = feeds.id
+WHERE saved_posts.user_id = $1
+ORDER BY saved_posts.created_at DESC
+LIMIT $2;
+
+-- name: MoveSavedPosts :exec
+UPDATE saved_posts
+SET post_id = target.id, updated_at = sqlc.arg(updated_at)
+FROM posts AS old, posts AS target
+WHERE saved_posts.post_id = old.id
+AND old.feed_id = sqlc.arg(from_feed_id)
+AND target.feed_id = sqlc.arg(to_feed_id)
+AND target.guid = old.guid
+AND NOT EXISTS (SELECT 1 FROM saved_posts AS existing WHERE existing.user_id = saved_posts.user_id AND existing.post_id = target.id);
\ No newline at end of file
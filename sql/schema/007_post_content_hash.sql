@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE posts ADD item_updated_at TIMESTAMP;
+ALTER TABLE posts ADD content_hash TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE posts DROP COLUMN content_hash;
+ALTER TABLE posts DROP COLUMN item_updated_at;
\ No newline at end of file
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE posts ADD content TEXT NOT NULL DEFAULT '';
+ALTER TABLE posts ADD authors TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE posts ADD categories TEXT[] NOT NULL DEFAULT '{}';
+
+-- +goose Down
+ALTER TABLE posts DROP COLUMN categories;
+ALTER TABLE posts DROP COLUMN authors;
+ALTER TABLE posts DROP COLUMN content;
\ No newline at end of file
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE feeds ADD etag TEXT;
+ALTER TABLE feeds ADD last_modified TEXT;
+
+-- +goose Down
+ALTER TABLE feeds DROP COLUMN last_modified;
+ALTER TABLE feeds DROP COLUMN etag;
\ No newline at end of file
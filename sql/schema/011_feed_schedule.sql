@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE feeds ADD next_fetch_at TIMESTAMP;
+ALTER TABLE feeds ADD fetch_interval_seconds INTEGER NOT NULL DEFAULT 3600;
+ALTER TABLE feeds ADD fetch_interval_override_seconds INTEGER;
+
+-- +goose Down
+ALTER TABLE feeds DROP COLUMN fetch_interval_override_seconds;
+ALTER TABLE feeds DROP COLUMN fetch_interval_seconds;
+ALTER TABLE feeds DROP COLUMN next_fetch_at;
\ No newline at end of file
@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE feeds ADD last_error TEXT;
+ALTER TABLE feeds ADD last_error_at TIMESTAMP;
+ALTER TABLE feeds ADD consecutive_failures INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE feeds ADD disabled_at TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE feeds DROP COLUMN disabled_at;
+ALTER TABLE feeds DROP COLUMN consecutive_failures;
+ALTER TABLE feeds DROP COLUMN last_error_at;
+ALTER TABLE feeds DROP COLUMN last_error;
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE feed_fetches ADD redirected_to TEXT;
+
+-- +goose Down
+ALTER TABLE feed_fetches DROP COLUMN redirected_to;
\ No newline at end of file
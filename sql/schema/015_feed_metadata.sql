@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE feeds ADD description TEXT;
+ALTER TABLE feeds ADD site_url TEXT;
+ALTER TABLE feeds ADD icon_url TEXT;
+
+-- +goose Down
+ALTER TABLE feeds DROP COLUMN icon_url;
+ALTER TABLE feeds DROP COLUMN site_url;
+ALTER TABLE feeds DROP COLUMN description;
\ No newline at end of file